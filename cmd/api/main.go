@@ -26,34 +26,77 @@ func main() {
 	fmt.Printf("🚀 Server sedang berjalan di port %s\n", port)
 	fmt.Println("📋 Endpoint yang tersedia:")
 	fmt.Println("   🔍 GET /health                          -> Health check")
+	fmt.Println("   🔑 GET /.well-known/jwks.json           -> Public key untuk verifikasi token")
 	fmt.Println("")
 	fmt.Println("   📝 Auth Endpoints:")
 	fmt.Println("   - POST /api/v1/auth/login               -> Login")
-	fmt.Println("   - POST /api/v1/auth/refresh             -> Refresh token")
-	fmt.Println("   - POST /api/v1/auth/logout              -> Logout (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/login/2fa           -> Verifikasi kode 2FA saat login")
+	fmt.Println("   - POST /api/v1/auth/passkeys/login/begin  -> Mulai login dengan passkey")
+	fmt.Println("   - POST /api/v1/auth/passkeys/login/finish -> Selesaikan login dengan passkey")
+	fmt.Println("   - POST /api/v1/auth/refresh             -> Refresh token (header X-CSRF-Token)")
+	fmt.Println("   - GET /api/v1/auth/csrf                 -> Ambil CSRF token untuk refresh/logout")
+	fmt.Println("   - POST /api/v1/auth/forgot-password     -> Kirim email reset password")
+	fmt.Println("   - POST /api/v1/auth/reset-password      -> Reset password dengan token")
+	fmt.Println("   - GET /api/v1/auth/password-policy      -> Lihat aturan password")
+	fmt.Println("   - GET /api/v1/auth/sso/login            -> Login lewat SSO/OIDC (jika OIDC_ISSUER diisi)")
+	fmt.Println("   - GET /api/v1/auth/sso/callback         -> Callback dari identity provider")
+	fmt.Println("   - POST /api/v1/auth/accept-invitation   -> Terima undangan admin dan pilih password")
+	fmt.Println("   - POST /api/v1/setup                    -> Buat super admin pertama dengan setup token")
+	fmt.Println("   - POST /api/v1/auth/logout              -> Logout (perlu auth + header X-CSRF-Token)")
 	fmt.Println("   - GET /api/v1/auth/profile              -> Lihat profil (perlu auth)")
 	fmt.Println("   - PUT /api/v1/auth/profile              -> Update profil (perlu auth)")
+	fmt.Println("   - GET /api/v1/auth/profile/security     -> Riwayat login dan kejadian keamanan (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/change-password     -> Ganti password, wajib jika must_change_password (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/2fa/setup           -> Mulai setup 2FA TOTP (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/2fa/enable          -> Aktifkan 2FA + recovery codes (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/2fa/disable         -> Nonaktifkan 2FA (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/2fa/recovery-codes  -> Buat ulang recovery codes (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/passkeys/register/begin  -> Mulai daftar passkey (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/passkeys/register/finish -> Simpan passkey baru (perlu auth)")
+	fmt.Println("   - GET /api/v1/auth/passkeys             -> Daftar passkey (perlu auth)")
+	fmt.Println("   - DELETE /api/v1/auth/passkeys/:id      -> Hapus passkey (perlu auth)")
+	fmt.Println("   - GET /api/v1/auth/sessions             -> Lihat sesi login aktif (perlu auth)")
+	fmt.Println("   - DELETE /api/v1/auth/sessions/:id      -> Cabut satu sesi (perlu auth)")
+	fmt.Println("   - DELETE /api/v1/auth/sessions          -> Cabut semua sesi (perlu auth)")
+	fmt.Println("   - POST /api/v1/auth/logout-all          -> Logout dari semua perangkat (perlu auth)")
 	fmt.Println("")
 	fmt.Println("   📰 Public News Endpoints:")
-	fmt.Println("   - GET /api/v1/news                      -> Lihat semua berita")
+	fmt.Println("   - GET /api/v1/news                      -> Lihat semua berita (filter ?category=&tag=)")
 	fmt.Println("   - GET /api/v1/news/:id                  -> Lihat berita by ID")
 	fmt.Println("   - GET /api/v1/news/slug/:slug           -> Lihat berita by slug")
+	fmt.Println("   - GET /api/v1/news/search?q=            -> Cari berita (relevansi, filter category/tag/from/to)")
+	fmt.Println("   - GET /api/v1/categories                -> Lihat kategori beserta jumlah berita")
+	fmt.Println("   - GET /api/v1/tags                      -> Lihat tag beserta jumlah berita")
 	fmt.Println("")
 	fmt.Println("   👥 Public Member Endpoints:")
 	fmt.Println("   - GET /api/v1/members                   -> Lihat semua anggota")
 	fmt.Println("   - GET /api/v1/members/:id               -> Lihat anggota by ID")
 	fmt.Println("")
-	fmt.Println("   🔒 Admin News Management (perlu role admin+):")
-	fmt.Println("   - GET /api/v1/admin/news                -> Lihat semua berita (admin)")
+	fmt.Println("   🔒 Admin News Management (perlu permission news:*):")
+	fmt.Println("   - GET /api/v1/admin/news                -> Lihat semua berita (admin, filter ?status=)")
 	fmt.Println("   - GET /api/v1/admin/news/:id            -> Lihat berita by ID (admin)")
 	fmt.Println("   - POST /api/v1/admin/news               -> Buat berita baru")
 	fmt.Println("   - PUT /api/v1/admin/news/:id            -> Update berita")
 	fmt.Println("   - DELETE /api/v1/admin/news/:id         -> Hapus berita")
+	fmt.Println("   - GET /api/v1/admin/news/search?q=      -> Cari berita semua status termasuk draft")
 	fmt.Println("   - GET /api/v1/admin/news/drafts         -> Lihat draft berita")
 	fmt.Println("   - GET /api/v1/admin/news/drafts/:id     -> Lihat draft by ID")
-	fmt.Println("   - POST /api/v1/admin/news/drafts/:id/publish -> Publish draft")
+	fmt.Println("   - POST /api/v1/admin/news/drafts/:id/publish -> Publish berita yang sudah disetujui")
+	fmt.Println("   - POST /api/v1/admin/news/:id/schedule -> Jadwalkan tayang/expire (publish_at, unpublish_at)")
+	fmt.Println("   - PUT /api/v1/admin/news/:id/schedule -> Ubah jadwal")
+	fmt.Println("   - DELETE /api/v1/admin/news/:id/schedule -> Batalkan jadwal")
+	fmt.Println("   - GET /api/v1/admin/news/:id/revisions -> Riwayat revisi berita")
+	fmt.Println("   - GET /api/v1/admin/news/:id/revisions/diff?from=&to= -> Bandingkan dua revisi")
+	fmt.Println("   - POST /api/v1/admin/news/:id/revisions/:revision/restore -> Kembalikan revisi")
+	fmt.Println("   - GET /api/v1/admin/news/workflow -> Lihat workflow redaksi")
+	fmt.Println("   - POST /api/v1/admin/news/:id/transition -> Pindah status (review, approve, minta perubahan)")
+	fmt.Println("   - GET /api/v1/admin/news/:id/transitions -> Riwayat status dan komentar reviewer")
+	fmt.Println("   - GET/POST /api/v1/admin/categories     -> Lihat/buat kategori (news:taxonomy)")
+	fmt.Println("   - PUT/DELETE /api/v1/admin/categories/:id -> Ubah/hapus kategori (?move_to=)")
+	fmt.Println("   - GET/POST /api/v1/admin/tags           -> Lihat/buat tag (news:taxonomy)")
+	fmt.Println("   - PUT/DELETE /api/v1/admin/tags/:id     -> Ubah/hapus tag")
 	fmt.Println("")
-	fmt.Println("   🔒 Admin Member Management (perlu role admin+):")
+	fmt.Println("   🔒 Admin Member Management (perlu permission members:*):")
 	fmt.Println("   - GET /api/v1/admin/members             -> Lihat semua anggota (admin)")
 	fmt.Println("   - GET /api/v1/admin/members/:id         -> Lihat anggota by ID (admin)")
 	fmt.Println("   - POST /api/v1/admin/members            -> Buat anggota baru")
@@ -61,11 +104,41 @@ func main() {
 	fmt.Println("   - DELETE /api/v1/admin/members/:id      -> Hapus anggota")
 	fmt.Println("")
 	fmt.Println("   👑 Super Admin Management (perlu role super_admin):")
-	fmt.Println("   - POST /api/v1/super-admin/admins       -> Buat admin baru")
+	fmt.Println("   - GET /api/v1/super-admin/admins        -> Lihat semua admin (paginasi, search)")
+	fmt.Println("   - GET /api/v1/super-admin/admins/:id    -> Lihat admin by ID")
+	fmt.Println("   - POST /api/v1/super-admin/admins       -> Buat admin baru (wajib ganti password saat login pertama)")
+	fmt.Println("   - PUT /api/v1/super-admin/admins/:id    -> Update admin")
+	fmt.Println("   - POST /api/v1/super-admin/admins/:id/disable -> Nonaktifkan admin")
+	fmt.Println("   - POST /api/v1/super-admin/admins/:id/enable  -> Aktifkan kembali admin")
+	fmt.Println("   - PUT /api/v1/super-admin/admins/:id/role     -> Ubah role admin")
+	fmt.Println("   - DELETE /api/v1/super-admin/admins/:id -> Hapus admin")
+	fmt.Println("   - GET /api/v1/super-admin/admins/:id/security-events -> Riwayat login dan keamanan user")
+	fmt.Println("   - POST /api/v1/super-admin/impersonation/start -> Pakai akun admin lain sementara (header X-Impersonated-By)")
+	fmt.Println("   - POST /api/v1/super-admin/impersonation/stop  -> Akhiri impersonation")
+	fmt.Println("   - GET /api/v1/super-admin/invitations   -> Lihat undangan yang masih terbuka")
+	fmt.Println("   - POST /api/v1/super-admin/invitations  -> Undang admin lewat email dengan role")
+	fmt.Println("   - POST /api/v1/super-admin/invitations/:id/resend -> Kirim ulang undangan (link lama tidak berlaku)")
+	fmt.Println("   - DELETE /api/v1/super-admin/invitations/:id -> Cabut undangan")
+	fmt.Println("   - GET /api/v1/super-admin/roles         -> Lihat role dan permission")
+	fmt.Println("   - GET /api/v1/super-admin/roles/permissions -> Lihat daftar permission")
+	fmt.Println("   - POST /api/v1/super-admin/roles        -> Buat role custom")
+	fmt.Println("   - PUT /api/v1/super-admin/roles/:name   -> Ubah permission role")
+	fmt.Println("   - DELETE /api/v1/super-admin/roles/:name -> Hapus role custom")
+	fmt.Println("   - GET /api/v1/super-admin/api-keys      -> Lihat API key")
+	fmt.Println("   - POST /api/v1/super-admin/api-keys     -> Buat API key (ditampilkan sekali)")
+	fmt.Println("   - DELETE /api/v1/super-admin/api-keys/:id -> Cabut API key")
+	fmt.Println("   - GET /api/v1/super-admin/audit-logs    -> Audit log (filter actor_id, impersonator_id, entity_type, action, from, to)")
+	fmt.Println("   - GET /api/v1/super-admin/settings/two-factor -> Lihat kebijakan 2FA")
+	fmt.Println("   - PUT /api/v1/super-admin/settings/two-factor -> Wajibkan 2FA untuk super admin")
+	fmt.Println("   - GET /api/v1/super-admin/settings/ip-access -> Lihat allowlist IP/negara route admin")
+	fmt.Println("   - PUT /api/v1/super-admin/settings/ip-access -> Ubah allowlist tanpa restart")
+	fmt.Println("   - DELETE /api/v1/super-admin/settings/ip-access -> Kembali ke allowlist dari env")
+	fmt.Println("   - GET/PUT /api/v1/super-admin/settings/news-workflow -> Lihat/ubah transisi workflow redaksi")
+	fmt.Println("   - DELETE /api/v1/super-admin/settings/news-workflow -> Kembali ke workflow default")
+	fmt.Println("   - GET /api/v1/super-admin/lockouts      -> Lihat username/IP yang terkunci")
+	fmt.Println("   - DELETE /api/v1/super-admin/lockouts/:scope/:value -> Buka kunci (scope: username | ip)")
 	fmt.Println("")
-	fmt.Println("📚 Default Super Admin:")
-	fmt.Println("   Username: superadmin")
-	fmt.Println("   Password: superadmin123")
+	fmt.Println("👑 Belum ada super admin? Isi BOOTSTRAP_ADMIN_* atau pakai setup token yang dicetak di log")
 	// Start server
 	if err := application.Router.Run(":" + port); err != nil {
 		log.Fatal("❌ Server gagal berjalan:", err)
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Generates a JWT signing key pair as <kid>.pem (private) and <kid>.pub.pem (public)
+func main() {
+	algorithm := flag.String("alg", "EdDSA", "key algorithm: EdDSA or RS256")
+	dir := flag.String("dir", "./keys", "directory to write the key files to")
+	kid := flag.String("kid", time.Now().Format("2006-01-02"), "key id, newest kid is used for signing when JWT_ACTIVE_KEY_ID is empty")
+	flag.Parse()
+
+	var private crypto.Signer
+	var err error
+	switch *algorithm {
+	case "EdDSA":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case "RS256":
+		private, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		log.Fatalf("❌ Unsupported algorithm %q, use EdDSA or RS256", *algorithm)
+	}
+	if err != nil {
+		log.Fatal("❌ Failed to generate key:", err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		log.Fatal("❌ Failed to encode private key:", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		log.Fatal("❌ Failed to encode public key:", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		log.Fatal("❌ Failed to create key directory:", err)
+	}
+
+	privatePath := filepath.Join(*dir, *kid+".pem")
+	publicPath := filepath.Join(*dir, *kid+".pub.pem")
+
+	if _, err := os.Stat(privatePath); err == nil {
+		log.Fatalf("❌ %s already exists, choose another -kid", privatePath)
+	}
+
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		log.Fatal("❌ Failed to write private key:", err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		log.Fatal("❌ Failed to write public key:", err)
+	}
+
+	fmt.Printf("✅ %s key pair created\n", *algorithm)
+	fmt.Printf("   Private key: %s\n", privatePath)
+	fmt.Printf("   Public key:  %s\n", publicPath)
+	fmt.Printf("   Set JWT_ALGORITHM=%s and JWT_ACTIVE_KEY_ID=%s, then restart or send SIGHUP\n", *algorithm, *kid)
+}
@@ -3,7 +3,8 @@ package main
 import (
 	"haslaw-be-services/internal/config"
 	"haslaw-be-services/internal/models"
-	"haslaw-be-services/internal/utils"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/service"
 	"log"
 
 	"github.com/joho/godotenv"
@@ -30,6 +31,22 @@ func main() {
 		&models.News{},
 		&models.Member{},
 		&models.BlacklistedToken{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.SystemSetting{},
+		&models.Session{},
+		&models.Role{},
+		&models.APIKey{},
+		&models.AuditLog{},
+		&models.PasswordHistory{},
+		&models.Invitation{},
+		&models.UserIdentity{},
+		&models.Passkey{},
+		&models.SecurityEvent{},
+		&models.NewsRevision{},
+		&models.NewsTransition{},
+		&models.Category{},
+		&models.Tag{},
 	); err != nil {
 		log.Fatal("❌ Failed to migrate database:", err)
 	}
@@ -37,7 +54,7 @@ func main() {
 
 	// Step 2: Ensure role column exists and has proper default
 	log.Println("🔧 Ensuring role column configuration...")
-	if err := db.Exec("ALTER TABLE users MODIFY COLUMN role VARCHAR(20) NOT NULL DEFAULT 'admin'").Error; err != nil {
+	if err := db.Exec("ALTER TABLE users MODIFY COLUMN role VARCHAR(50) NOT NULL DEFAULT 'admin'").Error; err != nil {
 		log.Printf("⚠️  Warning: Could not modify role column (might be correct already): %v", err)
 	}
 
@@ -50,37 +67,8 @@ func main() {
 		log.Printf("✅ Updated %d existing users with admin role", result.RowsAffected)
 	}
 
-	// Step 4: Create default super admin if not exists
-	log.Println("👑 Creating default super admin...")
-	var existingSuperAdmin models.User
-	err = db.Where("username = ?", "superadmin").First(&existingSuperAdmin).Error
-
-	if err != nil {
-		// Super admin doesn't exist, create one
-		hashedPassword, err := utils.HashPassword("superadmin123")
-		if err != nil {
-			log.Fatal("❌ Failed to hash super admin password:", err)
-		}
-
-		superAdmin := models.User{
-			Username: "superadmin",
-			Email:    "superadmin@haslaw.com",
-			Password: hashedPassword,
-			Role:     models.SuperAdmin,
-		}
-
-		if err := db.Create(&superAdmin).Error; err != nil {
-			log.Fatal("❌ Failed to create super admin:", err)
-		}
-
-		log.Println("✅ Default super admin created successfully!")
-		log.Println("📝 Super Admin Credentials:")
-		log.Println("   Username: superadmin")
-		log.Println("   Password: superadmin123")
-		log.Println("   ⚠️  IMPORTANT: Please change this password after first login!")
-	} else {
-		log.Println("✅ Super admin already exists, skipping creation")
-	}
+	// Step 4: Super admin pertama dibuat oleh API saat startup (BOOTSTRAP_ADMIN_* atau setup token)
+	log.Println("👑 Super admin is bootstrapped when the API starts for the first time, see BOOTSTRAP_ADMIN_* in .env.example")
 
 	// Step 5: Create sample news if none exist (optional)
 	log.Println("📰 Checking for sample news...")
@@ -116,7 +104,16 @@ func main() {
 		log.Printf("✅ Found %d existing news articles, skipping sample creation", newsCount)
 	}
 
-	// Step 6: Cleanup expired blacklisted tokens (if any)
+	// Step 6: Normalize free-text news categories into the categories table
+	log.Println("🏷️  Normalizing news categories...")
+	normalized, err := service.NewCategoryService(repository.NewCategoryRepository(db)).NormalizeLegacy()
+	if err != nil {
+		log.Printf("⚠️  Warning: Could not normalize news categories: %v", err)
+	} else {
+		log.Printf("✅ Linked %d news articles to categories", normalized)
+	}
+
+	// Step 7: Cleanup expired blacklisted tokens (if any)
 	log.Println("🧹 Cleaning up expired blacklisted tokens...")
 	result = db.Where("expires_at < NOW()").Delete(&models.BlacklistedToken{})
 	if result.Error != nil {
@@ -125,11 +122,11 @@ func main() {
 		log.Printf("✅ Cleaned up %d expired tokens", result.RowsAffected)
 	}
 
-	// Step 7: Verify database structure
+	// Step 8: Verify database structure
 	log.Println("🔍 Verifying database structure...")
 
 	// Check if all tables exist
-	tables := []string{"users", "news", "members", "blacklisted_tokens"}
+	tables := []string{"users", "news", "members", "blacklisted_tokens", "password_reset_tokens", "recovery_codes", "system_settings", "sessions", "roles", "api_keys", "audit_logs", "password_histories", "invitations", "user_identities", "passkeys", "security_events", "news_revisions", "news_transitions", "categories", "tags", "news_tags"}
 	for _, table := range tables {
 		var count int64
 		if err := db.Raw("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", table).Scan(&count).Error; err != nil {
@@ -2,7 +2,11 @@ package app
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"haslaw-be-services/internal/config"
 	"haslaw-be-services/internal/handlers"
@@ -20,6 +24,34 @@ import (
 type App struct {
 	DB     *gorm.DB
 	Router *gin.Engine
+	Config *config.Config
+
+	authService          service.AuthService
+	roleService          service.RoleService
+	apiKeyService        service.APIKeyService
+	auditService         service.AuditService
+	ipAccessService      service.IPAccessService
+	authHandler          *handlers.AuthHandler
+	adminHandler         *handlers.AdminHandler
+	twoFactorHandler     *handlers.TwoFactorHandler
+	passkeyHandler       *handlers.PasskeyHandler
+	securityEventHandler *handlers.SecurityEventHandler
+	impersonationHandler *handlers.ImpersonationHandler
+	ipAccessHandler      *handlers.IPAccessHandler
+	lockoutHandler       *handlers.LockoutHandler
+	roleHandler          *handlers.RoleHandler
+	apiKeyHandler        *handlers.APIKeyHandler
+	auditHandler         *handlers.AuditHandler
+	jwksHandler          *handlers.JWKSHandler
+	newsHandler          *handlers.NewsHandler
+	newsWorkflowHandler  *handlers.NewsWorkflowHandler
+	categoryHandler      *handlers.CategoryHandler
+	tagHandler           *handlers.TagHandler
+	memberHandler        *handlers.MemberHandler
+	invitationHandler    *handlers.InvitationHandler
+	ssoHandler           *handlers.SSOHandler
+	setupHandler         *handlers.SetupHandler
+	healthHandler        *handlers.HealthHandler
 }
 
 func New() (*App, error) {
@@ -49,8 +81,24 @@ func New() (*App, error) {
 	app := &App{
 		DB:     db,
 		Router: gin.New(),
+		Config: config.LoadConfig(),
 	}
 
+	keyRing, err := app.initializeKeyRing()
+	if err != nil {
+		return nil, fmt.Errorf("JWT key ring initialization failed: %w", err)
+	}
+	app.jwksHandler = handlers.NewJWKSHandler(keyRing)
+
+	cookie := app.Config.Cookie
+	utils.SetCookieConfig(utils.CookieConfig{
+		Secure:     cookie.Secure,
+		SameSite:   utils.ParseSameSite(cookie.SameSite),
+		Domain:     cookie.Domain,
+		Path:       cookie.Path,
+		HostPrefix: cookie.HostPrefix,
+	})
+
 	if err := app.initializeServices(); err != nil {
 		return nil, fmt.Errorf("service initialization failed: %w", err)
 	}
@@ -81,6 +129,22 @@ func runMigrations(db *gorm.DB) error {
 		&models.News{},
 		&models.Member{},
 		&models.BlacklistedToken{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.SystemSetting{},
+		&models.Session{},
+		&models.Role{},
+		&models.APIKey{},
+		&models.AuditLog{},
+		&models.PasswordHistory{},
+		&models.Invitation{},
+		&models.UserIdentity{},
+		&models.Passkey{},
+		&models.SecurityEvent{},
+		&models.NewsRevision{},
+		&models.NewsTransition{},
+		&models.Category{},
+		&models.Tag{},
 	)
 }
 
@@ -89,22 +153,184 @@ func (a *App) initializeServices() error {
 	userRepo := repository.NewUserRepository(a.DB)
 	blacklistRepo := repository.NewBlacklistRepository(a.DB)
 	newsRepo := repository.NewNewsRepository(a.DB)
+	newsRevisionRepo := repository.NewNewsRevisionRepository(a.DB)
+	newsTransitionRepo := repository.NewNewsTransitionRepository(a.DB)
+	categoryRepo := repository.NewCategoryRepository(a.DB)
+	tagRepo := repository.NewTagRepository(a.DB)
 	memberRepo := repository.NewMemberRepository(a.DB)
+	passwordResetRepo := repository.NewPasswordResetRepository(a.DB)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(a.DB)
+	settingRepo := repository.NewSettingRepository(a.DB)
+	sessionRepo := repository.NewSessionRepository(a.DB)
+	roleRepo := repository.NewRoleRepository(a.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(a.DB)
+	auditLogRepo := repository.NewAuditLogRepository(a.DB)
+	passwordHistoryRepo := repository.NewPasswordHistoryRepository(a.DB)
+	invitationRepo := repository.NewInvitationRepository(a.DB)
+	userIdentityRepo := repository.NewUserIdentityRepository(a.DB)
+	passkeyRepo := repository.NewPasskeyRepository(a.DB)
+	securityEventRepo := repository.NewSecurityEventRepository(a.DB)
+
+	mailer := a.newMailer()
+	loginThrottler := service.NewLoginThrottler(service.LoginThrottleConfig(a.Config.LoginThrottle))
+
+	breachedPasswords, err := service.NewLocalBreachedPasswordSource(a.Config.Password.BreachedListPath)
+	if err != nil {
+		return err
+	}
+	passwordPolicy := service.NewPasswordPolicy(service.PasswordPolicyConfig{
+		MinLength:        a.Config.Password.MinLength,
+		RequireUppercase: a.Config.Password.RequireUppercase,
+		RequireLowercase: a.Config.Password.RequireLowercase,
+		RequireDigit:     a.Config.Password.RequireDigit,
+		RequireSymbol:    a.Config.Password.RequireSymbol,
+		HistorySize:      a.Config.Password.HistorySize,
+	}, passwordHistoryRepo, breachedPasswords)
+
+	revocations, err := service.NewTokenRevocationList(blacklistRepo)
+	if err != nil {
+		return fmt.Errorf("failed to load revoked tokens: %w", err)
+	}
+
+	network := a.Config.Network
+	ipAccessService, err := service.NewIPAccessService(
+		settingRepo,
+		models.IPAccessRules{
+			Admin:      models.IPAccessRule{AllowedIPs: network.AdminAllowedIPs, AllowedCountries: network.AdminAllowedCountries},
+			SuperAdmin: models.IPAccessRule{AllowedIPs: network.SuperAdminAllowedIPs, AllowedCountries: network.SuperAdminAllowedCountries},
+		},
+		network.TrustedProxies,
+		network.CountryHeader,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load IP access rules: %w", err)
+	}
+
+	newsWorkflowService, err := service.NewNewsWorkflowService(settingRepo)
+	if err != nil {
+		return fmt.Errorf("failed to load news workflow: %w", err)
+	}
 
-	authService := service.NewAuthService(userRepo, blacklistRepo)
-	newsService := service.NewNewsService(newsRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	authService := service.NewAuthService(userRepo, revocations, settingRepo, sessionRepo, loginThrottler, passwordPolicy, roleService)
+	newsService := service.NewNewsService(newsRepo, newsRevisionRepo, newsTransitionRepo, newsWorkflowService, categoryRepo, tagRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	tagService := service.NewTagService(tagRepo)
 	memberService := service.NewMemberService(memberRepo)
+	userService := service.NewUserService(userRepo, roleService, authService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, roleService)
+	auditService := service.NewAuditService(auditLogRepo)
+	securityEventService := service.NewSecurityEventService(securityEventRepo, userRepo, a.Config.Revocation.SecurityEventRetention)
+	impersonationService := service.NewImpersonationService(userRepo, sessionRepo, a.Config.Impersonation.MaxTTL)
+	passwordResetService := service.NewPasswordResetService(
+		userRepo,
+		passwordResetRepo,
+		authService,
+		passwordPolicy,
+		mailer,
+		a.Config.PasswordReset.URL,
+		a.Config.PasswordReset.TTL,
+	)
+	invitationService := service.NewInvitationService(
+		invitationRepo,
+		userRepo,
+		roleService,
+		passwordPolicy,
+		mailer,
+		a.Config.Invitation.URL,
+		a.Config.Invitation.TTL,
+	)
+	twoFactorService := service.NewTwoFactorService(
+		userRepo,
+		recoveryCodeRepo,
+		settingRepo,
+		authService,
+		loginThrottler,
+		a.Config.TwoFactor.Issuer,
+	)
+	passkeyService := service.NewPasskeyService(
+		passkeyRepo,
+		userRepo,
+		settingRepo,
+		authService,
+		revocations,
+		utils.WebAuthnConfig{
+			RPID:    a.Config.WebAuthn.RPID,
+			RPName:  a.Config.WebAuthn.RPName,
+			Origins: a.Config.WebAuthn.Origins,
+		},
+		a.Config.WebAuthn.Attestation,
+	)
+
+	if err := roleService.EnsureBuiltInRoles(); err != nil {
+		return fmt.Errorf("failed to create built-in roles: %w", err)
+	}
+
+	// Kategori teks lama dipindahkan ke tabel categories, berita yang sudah punya category_id tidak disentuh
+	if normalized, err := categoryService.NormalizeLegacy(); err != nil {
+		log.Printf("Warning: Failed to normalize news categories: %v", err)
+	} else if normalized > 0 {
+		log.Printf("Linked %d news to categories", normalized)
+	}
 
-	if err := authService.CreateDefaultSuperAdmin(); err != nil {
-		return fmt.Errorf("failed to create default super admin: %w", err)
+	bootstrapService := service.NewBootstrapService(userRepo, settingRepo, authService, passwordPolicy, service.BootstrapConfig(a.Config.Bootstrap))
+	if err := bootstrapService.Bootstrap(); err != nil {
+		return fmt.Errorf("failed to bootstrap super admin: %w", err)
 	}
 
-	authHandler := handlers.NewAuthHandler(authService)
-	adminHandler := handlers.NewAdminHandler(authService, userRepo)
-	newsHandler := handlers.NewNewsHandler(newsService)
-	memberHandler := handlers.NewMemberHandler(memberService)
+	a.startBackgroundJobs(revocations, authService, passwordResetService, securityEventService)
+	a.startIPAccessSync(ipAccessService)
+	a.startNewsWorkflowSync(newsWorkflowService)
+	a.startRoleSync(roleService)
+	a.startNewsScheduler(service.NewNewsScheduler(newsRepo, newsRevisionRepo, newsTransitionRepo, auditService))
+
+	authHandler := handlers.NewAuthHandler(authService, passwordResetService, twoFactorService, auditService, securityEventService, passwordPolicy)
+	adminHandler := handlers.NewAdminHandler(authService, userService, userRepo, auditService, securityEventService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService, authService)
+	passkeyHandler := handlers.NewPasskeyHandler(passkeyService, authService, auditService, securityEventService)
+	securityEventHandler := handlers.NewSecurityEventHandler(securityEventService, userService)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationService, auditService, securityEventService)
+	ipAccessHandler := handlers.NewIPAccessHandler(ipAccessService, auditService)
+	lockoutHandler := handlers.NewLockoutHandler(loginThrottler)
+	roleHandler := handlers.NewRoleHandler(roleService, auditService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, auditService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	newsHandler := handlers.NewNewsHandler(newsService, auditService)
+	newsWorkflowHandler := handlers.NewNewsWorkflowHandler(newsWorkflowService, auditService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, auditService)
+	tagHandler := handlers.NewTagHandler(tagService, auditService)
+	memberHandler := handlers.NewMemberHandler(memberService, auditService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService, auditService)
+	ssoHandler := a.newSSOHandler(userRepo, userIdentityRepo, roleService, authService, auditService, securityEventService)
+	setupHandler := handlers.NewSetupHandler(bootstrapService, auditService)
 	healthHandler := handlers.NewHealthHandler()
 
+	a.authService = authService
+	a.roleService = roleService
+	a.apiKeyService = apiKeyService
+	a.auditService = auditService
+	a.ipAccessService = ipAccessService
+	a.authHandler = authHandler
+	a.adminHandler = adminHandler
+	a.twoFactorHandler = twoFactorHandler
+	a.passkeyHandler = passkeyHandler
+	a.securityEventHandler = securityEventHandler
+	a.impersonationHandler = impersonationHandler
+	a.ipAccessHandler = ipAccessHandler
+	a.lockoutHandler = lockoutHandler
+	a.roleHandler = roleHandler
+	a.apiKeyHandler = apiKeyHandler
+	a.auditHandler = auditHandler
+	a.newsHandler = newsHandler
+	a.newsWorkflowHandler = newsWorkflowHandler
+	a.categoryHandler = categoryHandler
+	a.tagHandler = tagHandler
+	a.memberHandler = memberHandler
+	a.invitationHandler = invitationHandler
+	a.ssoHandler = ssoHandler
+	a.setupHandler = setupHandler
+	a.healthHandler = healthHandler
+
 	a.Router.Use(func(c *gin.Context) {
 		c.Set("authHandler", authHandler)
 		c.Set("adminHandler", adminHandler)
@@ -118,7 +344,210 @@ func (a *App) initializeServices() error {
 	return nil
 }
 
+// initializeKeyRing loads the JWT signing keys and reloads them on SIGHUP so keys can be rotated without a restart
+func (a *App) initializeKeyRing() (*utils.KeyRing, error) {
+	jwtConfig := a.Config.JWT
+	keyRing, err := utils.NewKeyRing(utils.KeyRingConfig{
+		Algorithm:   jwtConfig.Algorithm,
+		Secret:      jwtConfig.SecretKey,
+		KeysDir:     jwtConfig.KeysDir,
+		ActiveKeyID: jwtConfig.ActiveKeyID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.SetKeyRing(keyRing)
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := keyRing.Reload(); err != nil {
+				log.Printf("Warning: Failed to reload JWT keys, keeping the current keys: %v", err)
+				continue
+			}
+			log.Println("🔑 JWT keys reloaded")
+		}
+	}()
+
+	return keyRing, nil
+}
+
+// startBackgroundJobs keeps the revoked token cache in sync with other instances and
+// periodically deletes expired blacklist entries, sessions and reset tokens
+func (a *App) startBackgroundJobs(
+	revocations service.TokenRevocationList,
+	authService service.AuthService,
+	passwordResetService service.PasswordResetService,
+	securityEventService service.SecurityEventService,
+) {
+	if a.Config.Revocation.SyncInterval <= 0 || a.Config.Revocation.CleanupInterval <= 0 {
+		log.Println("Warning: Token revocation sync or cleanup interval is not positive, background jobs disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(a.Config.Revocation.SyncInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := revocations.Sync(); err != nil {
+				log.Printf("Warning: Failed to sync revoked tokens: %v", err)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(a.Config.Revocation.CleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := authService.CleanupExpiredTokens(); err != nil {
+				log.Printf("Warning: Failed to clean up expired tokens: %v", err)
+			}
+			if err := passwordResetService.CleanupExpiredTokens(); err != nil {
+				log.Printf("Warning: Failed to clean up expired password reset tokens: %v", err)
+			}
+			if err := securityEventService.CleanupExpired(); err != nil {
+				log.Printf("Warning: Failed to clean up old security events: %v", err)
+			}
+		}
+	}()
+}
+
+// startIPAccessSync reloads the IP access rules, so changes made through the API on
+// another instance take effect here as well
+func (a *App) startIPAccessSync(ipAccessService service.IPAccessService) {
+	interval := a.Config.Network.IPAccessSyncInterval
+	if interval <= 0 {
+		log.Println("Warning: IP_ACCESS_SYNC_INTERVAL is not positive, IP access rules are only loaded at startup")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := ipAccessService.Sync(); err != nil {
+				log.Printf("Warning: Failed to sync IP access rules: %v", err)
+			}
+		}
+	}()
+}
+
+// startNewsWorkflowSync reloads the news workflow, so changes made through the API on
+// another instance take effect here as well
+func (a *App) startNewsWorkflowSync(newsWorkflowService service.NewsWorkflowService) {
+	interval := a.Config.News.WorkflowSyncInterval
+	if interval <= 0 {
+		log.Println("Warning: NEWS_WORKFLOW_SYNC_INTERVAL is not positive, the news workflow is only loaded at startup")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := newsWorkflowService.Sync(); err != nil {
+				log.Printf("Warning: Failed to sync news workflow: %v", err)
+			}
+		}
+	}()
+}
+
+// startRoleSync reloads the role permissions, so role changes made through the API on
+// another instance take effect here as well
+func (a *App) startRoleSync(roleService service.RoleService) {
+	interval := a.Config.Role.SyncInterval
+	if interval <= 0 {
+		log.Println("Warning: ROLE_SYNC_INTERVAL is not positive, role changes only apply on the instance that made them")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := roleService.Sync(); err != nil {
+				log.Printf("Warning: Failed to sync role permissions: %v", err)
+			}
+		}
+	}()
+}
+
+// startNewsScheduler publishes scheduled news and archives expired news in the background
+func (a *App) startNewsScheduler(scheduler service.NewsScheduler) {
+	interval := a.Config.News.SchedulerInterval
+	if interval <= 0 {
+		log.Println("Warning: NEWS_SCHEDULER_INTERVAL is not positive, news scheduler disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := scheduler.Run(); err != nil {
+				log.Printf("Warning: Failed to run news scheduler: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// newSSOHandler returns the OpenID Connect login handler, or nil when OIDC_ISSUER is not set
+func (a *App) newSSOHandler(
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	roleService service.RoleService,
+	authService service.AuthService,
+	auditService service.AuditService,
+	securityEventService service.SecurityEventService,
+) *handlers.SSOHandler {
+	sso := a.Config.SSO
+	if sso.Issuer == "" {
+		if !sso.PasswordLoginEnabled {
+			fmt.Println("⚠️  PASSWORD_LOGIN_ENABLED=false is ignored because OIDC_ISSUER is not set")
+		}
+		return nil
+	}
+
+	provider := utils.NewOIDCProvider(utils.OIDCConfig{
+		Issuer:       sso.Issuer,
+		ClientID:     sso.ClientID,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectURL,
+		Scopes:       sso.Scopes,
+	})
+	ssoService := service.NewSSOService(provider, userRepo, userIdentityRepo, roleService, authService, service.SSOConfig{
+		AutoProvision:        sso.AutoProvision,
+		DefaultRole:          models.UserRole(sso.DefaultRole),
+		RequireVerifiedEmail: sso.RequireVerifiedEmail,
+	})
+
+	return handlers.NewSSOHandler(ssoService, auditService, securityEventService, sso.PostLoginURL)
+}
+
+// passwordLoginEnabled reports whether password login routes are served. Password
+// login can only be turned off once SSO is configured, so admins cannot be locked out.
+func (a *App) passwordLoginEnabled() bool {
+	return a.Config.SSO.PasswordLoginEnabled || a.ssoHandler == nil
+}
+
+// newMailer returns an SMTP mailer when SMTP_HOST is set, otherwise emails are only logged
+func (a *App) newMailer() utils.Mailer {
+	mail := a.Config.Mail
+	if mail.Host == "" {
+		fmt.Println("⚠️  SMTP_HOST not set, emails will be written to the log")
+		return utils.NewLogMailer()
+	}
+	return utils.NewSMTPMailer(mail.Host, mail.Port, mail.Username, mail.Password, mail.From)
+}
+
 func (a *App) setupMiddleware() error {
+	// c.ClientIP() only trusts X-Forwarded-For / X-Real-IP from these proxies (nginx)
+	if err := a.Router.SetTrustedProxies(a.Config.Network.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
 	// Performance optimizations
 	a.Router.Use(gin.Recovery())
 
@@ -133,7 +562,7 @@ func (a *App) setupMiddleware() error {
 	// Core middlewares
 	a.Router.Use(middleware.TraceIDMiddleware())
 	a.Router.Use(middleware.RateLimitMiddleware(100)) // Increase rate limit
-	a.Router.Use(middleware.CORSMiddleware())
+	a.Router.Use(middleware.CORSMiddleware(a.Config.CORS.AllowedOrigins))
 	a.Router.Use(middleware.SecurityHeadersMiddleware())
 
 	// Static files with cache headers for better performance
@@ -147,6 +576,9 @@ func (a *App) setupRoutes() error {
 	healthHandler := a.getHealthHandler()
 	a.Router.GET("/health", healthHandler.Check)
 
+	// Public keys for verifying access tokens (RS256/EdDSA)
+	a.Router.GET("/.well-known/jwks.json", a.getJWKSHandler().GetKeys)
+
 	v1 := a.Router.Group("/api/v1")
 
 	a.setupPublicRoutes(v1)
@@ -158,37 +590,102 @@ func (a *App) setupRoutes() error {
 }
 
 func (a *App) getAuthHandler() *handlers.AuthHandler {
-	userRepo := repository.NewUserRepository(a.DB)
-	blacklistRepo := repository.NewBlacklistRepository(a.DB)
-	authService := service.NewAuthService(userRepo, blacklistRepo)
-	return handlers.NewAuthHandler(authService)
+	return a.authHandler
 }
 
 func (a *App) getAdminHandler() *handlers.AdminHandler {
-	userRepo := repository.NewUserRepository(a.DB)
-	blacklistRepo := repository.NewBlacklistRepository(a.DB)
-	authService := service.NewAuthService(userRepo, blacklistRepo)
-	return handlers.NewAdminHandler(authService, userRepo)
+	return a.adminHandler
+}
+
+func (a *App) getTwoFactorHandler() *handlers.TwoFactorHandler {
+	return a.twoFactorHandler
+}
+
+func (a *App) getPasskeyHandler() *handlers.PasskeyHandler {
+	return a.passkeyHandler
+}
+
+func (a *App) getSecurityEventHandler() *handlers.SecurityEventHandler {
+	return a.securityEventHandler
+}
+
+func (a *App) getImpersonationHandler() *handlers.ImpersonationHandler {
+	return a.impersonationHandler
+}
+
+func (a *App) getIPAccessHandler() *handlers.IPAccessHandler {
+	return a.ipAccessHandler
+}
+
+func (a *App) getNewsWorkflowHandler() *handlers.NewsWorkflowHandler {
+	return a.newsWorkflowHandler
+}
+
+func (a *App) getCategoryHandler() *handlers.CategoryHandler {
+	return a.categoryHandler
+}
+
+func (a *App) getTagHandler() *handlers.TagHandler {
+	return a.tagHandler
+}
+
+func (a *App) getLockoutHandler() *handlers.LockoutHandler {
+	return a.lockoutHandler
+}
+
+func (a *App) getRoleHandler() *handlers.RoleHandler {
+	return a.roleHandler
+}
+
+func (a *App) getAPIKeyHandler() *handlers.APIKeyHandler {
+	return a.apiKeyHandler
+}
+
+func (a *App) getAuditHandler() *handlers.AuditHandler {
+	return a.auditHandler
+}
+
+func (a *App) getJWKSHandler() *handlers.JWKSHandler {
+	return a.jwksHandler
 }
 
 func (a *App) getNewsHandler() *handlers.NewsHandler {
-	newsRepo := repository.NewNewsRepository(a.DB)
-	newsService := service.NewNewsService(newsRepo)
-	return handlers.NewNewsHandler(newsService)
+	return a.newsHandler
 }
 
 func (a *App) getMemberHandler() *handlers.MemberHandler {
-	memberRepo := repository.NewMemberRepository(a.DB)
-	memberService := service.NewMemberService(memberRepo)
-	return handlers.NewMemberHandler(memberService)
+	return a.memberHandler
+}
+
+func (a *App) getInvitationHandler() *handlers.InvitationHandler {
+	return a.invitationHandler
+}
+
+// getSSOHandler returns nil when OIDC_ISSUER is not set
+func (a *App) getSSOHandler() *handlers.SSOHandler {
+	return a.ssoHandler
+}
+
+func (a *App) getSetupHandler() *handlers.SetupHandler {
+	return a.setupHandler
 }
 
 func (a *App) getHealthHandler() *handlers.HealthHandler {
-	return handlers.NewHealthHandler()
+	return a.healthHandler
 }
 
 func (a *App) getAuthService() service.AuthService {
-	userRepo := repository.NewUserRepository(a.DB)
-	blacklistRepo := repository.NewBlacklistRepository(a.DB)
-	return service.NewAuthService(userRepo, blacklistRepo)
+	return a.authService
+}
+
+func (a *App) getRoleService() service.RoleService {
+	return a.roleService
+}
+
+func (a *App) getAPIKeyService() service.APIKeyService {
+	return a.apiKeyService
+}
+
+func (a *App) getIPAccessService() service.IPAccessService {
+	return a.ipAccessService
 }
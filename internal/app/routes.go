@@ -13,19 +13,43 @@ func (a *App) setupPublicRoutes(v1 *gin.RouterGroup) {
 	newsHandler := a.getNewsHandler()
 	memberHandler := a.getMemberHandler()
 
+	// First super admin, only available until setup is completed
+	v1.POST("/setup", a.getSetupHandler().Setup)
+
 	// Auth routes (public)
 	auth := v1.Group("/auth")
 	{
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/refresh", authHandler.RefreshToken)
+		if a.passwordLoginEnabled() {
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/forgot-password", authHandler.ForgotPassword)
+			auth.POST("/reset-password", authHandler.ResetPassword)
+		}
+		auth.POST("/login/2fa", authHandler.LoginTwoFactor) // Juga dipakai setelah login SSO
+
+		// Login dengan passkey, tanpa password atau sebagai faktor kedua (kirim challenge_token)
+		auth.POST("/passkeys/login/begin", a.getPasskeyHandler().BeginLogin)
+		auth.POST("/passkeys/login/finish", a.getPasskeyHandler().FinishLogin)
+
+		auth.POST("/refresh", middleware.CSRFMiddleware(), authHandler.RefreshToken)
+		auth.GET("/csrf", authHandler.CSRFToken)
+		auth.GET("/password-policy", authHandler.GetPasswordPolicy)
+		auth.POST("/accept-invitation", a.getInvitationHandler().AcceptInvitation)
+
+		// Single sign-on (OpenID Connect), hanya jika OIDC_ISSUER diisi
+		if ssoHandler := a.getSSOHandler(); ssoHandler != nil {
+			auth.GET("/sso/login", ssoHandler.Login)
+			auth.GET("/sso/callback", ssoHandler.Callback)
+		}
 	}
 
 	// Public news routes
 	news := v1.Group("/news")
 	{
-		news.GET("", newsHandler.GetAllPublicNews)     // Get all published news
-		news.GET("/:id", newsHandler.GetByID)          // Get news by ID
-		news.GET("/slug/:slug", newsHandler.GetBySlug) // Get news by slug
+		news.GET("", newsHandler.GetAllPublicNews)              // Get all published news
+		news.GET("/:id", newsHandler.GetPublishedByID)          // Get news by ID (hanya yang sedang tayang)
+		news.GET("/slug/:slug", newsHandler.GetPublishedBySlug) // Get news by slug (hanya yang sedang tayang)
+
+		news.GET("/search", newsHandler.SearchPublicNews) // Full-text search (?q=&category=&tag=&from=&to=), urut relevansi
 	}
 
 	// Public member routes
@@ -34,56 +58,130 @@ func (a *App) setupPublicRoutes(v1 *gin.RouterGroup) {
 		members.GET("", memberHandler.GetAll)
 		members.GET("/:id", memberHandler.GetByID)
 	}
+
+	// Kategori dan tag, news_count hanya menghitung berita yang sedang tayang
+	v1.GET("/categories", a.getCategoryHandler().ListPublic)
+	v1.GET("/tags", a.getTagHandler().ListPublic)
 }
 
 // setupAuthRoutes sets up routes that require authentication (admin or super admin)
 func (a *App) setupAuthRoutes(v1 *gin.RouterGroup) {
 	authHandler := a.getAuthHandler()
 	adminHandler := a.getAdminHandler()
+	twoFactorHandler := a.getTwoFactorHandler()
+	passkeyHandler := a.getPasskeyHandler()
 	authService := a.getAuthService()
 
 	// Routes requiring authentication
 	auth := v1.Group("/auth")
 	auth.Use(middleware.AuthMiddleware(authService))
 	{
-		auth.POST("/logout", authHandler.Logout)
+		auth.POST("/logout", middleware.CSRFMiddleware(), authHandler.Logout)
 		auth.GET("/profile", adminHandler.GetProfile)
 		auth.PUT("/profile", adminHandler.UpdateProfile)
+		auth.GET("/profile/security", a.getSecurityEventHandler().GetMySecurityEvents) // Riwayat login dan kejadian keamanan
+		auth.POST("/change-password", authHandler.ChangePassword)
+
+		// Two-factor authentication (TOTP)
+		auth.POST("/2fa/setup", twoFactorHandler.Setup)
+		auth.POST("/2fa/enable", twoFactorHandler.Enable)
+		auth.POST("/2fa/disable", twoFactorHandler.Disable)
+		auth.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+		// Passkeys (WebAuthn)
+		auth.POST("/passkeys/register/begin", passkeyHandler.BeginRegistration)
+		auth.POST("/passkeys/register/finish", passkeyHandler.FinishRegistration)
+		auth.GET("/passkeys", passkeyHandler.List)
+		auth.DELETE("/passkeys/:id", passkeyHandler.Delete)
+
+		// Session management (satu sesi = satu login / token family)
+		auth.GET("/sessions", authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", authHandler.RevokeSession)
+		auth.DELETE("/sessions", authHandler.LogoutAll)
+		auth.POST("/logout-all", authHandler.LogoutAll) // Cabut semua token di semua perangkat
 	}
 }
 
-// setupAdminRoutes sets up routes that require admin role or higher
+// setupAdminRoutes sets up content management routes, guarded per route by permission
 func (a *App) setupAdminRoutes(v1 *gin.RouterGroup) {
 	authService := a.getAuthService()
+	roleService := a.getRoleService()
+	apiKeyService := a.getAPIKeyService()
 	newsHandler := a.getNewsHandler()
+	newsWorkflowHandler := a.getNewsWorkflowHandler()
+	categoryHandler := a.getCategoryHandler()
+	tagHandler := a.getTagHandler()
 	memberHandler := a.getMemberHandler()
 
-	// Admin routes (admin and super admin can access)
+	can := func(permissions ...models.Permission) gin.HandlerFunc {
+		return middleware.RequirePermission(roleService, permissions...)
+	}
+	canAny := func(permissions ...models.Permission) gin.HandlerFunc {
+		return middleware.RequireAnyPermission(roleService, permissions...)
+	}
+
+	// Admin routes (semua role staf dan API key, akses ditentukan permission / scope)
 	admin := v1.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(authService))
-	admin.Use(middleware.RequireRole(models.Admin))
+	admin.Use(
+		middleware.IPAccessMiddleware(a.getIPAccessService(), models.IPAccessGroupAdmin),
+		middleware.AuthOrAPIKeyMiddleware(authService, apiKeyService),
+	)
 	{
 		// News management - CRUD lengkap
 		news := admin.Group("/news")
 		{
-			news.GET("", newsHandler.GetAll)                           // Get all news (admin view)
-			news.GET("/:id", newsHandler.GetByID)                      // Get specific news
-			news.POST("", newsHandler.Create)                          // Create news
-			news.PUT("/:id", newsHandler.Update)                       // Update news
-			news.DELETE("/:id", newsHandler.Delete)                    // Delete news
-			news.GET("/drafts", newsHandler.GetDrafts)                 // Get draft news
-			news.GET("/drafts/:id", newsHandler.GetDraftByID)          // Get draft by ID
-			news.POST("/drafts/:id/publish", newsHandler.PublishDraft) // Publish draft
+			news.GET("", can(models.PermissionNewsRead), newsHandler.GetAll)                                         // Get all news (admin view)
+			news.GET("/:id", can(models.PermissionNewsRead), newsHandler.GetByID)                                    // Get specific news
+			news.POST("", can(models.PermissionNewsCreate), newsHandler.Create)                                      // Create news (publish butuh news:publish)
+			news.PUT("/:id", canAny(models.PermissionNewsEditOwn, models.PermissionNewsEditAny), newsHandler.Update) // Update news (edit_own hanya milik sendiri)
+			news.DELETE("/:id", can(models.PermissionNewsDelete), newsHandler.Delete)                                // Delete news
+			news.GET("/drafts", can(models.PermissionNewsRead), newsHandler.GetDrafts)                               // Get draft news
+			news.GET("/drafts/:id", can(models.PermissionNewsRead), newsHandler.GetDraftByID)                        // Get draft by ID
+			news.POST("/drafts/:id/publish", can(models.PermissionNewsPublish), newsHandler.PublishDraft)            // Publish draft
+			news.POST("/:id/schedule", can(models.PermissionNewsPublish), newsHandler.Schedule)                      // Jadwalkan tayang (publish_at) dan/atau expire (unpublish_at)
+			news.PUT("/:id/schedule", can(models.PermissionNewsPublish), newsHandler.Reschedule)                     // Ubah jadwal
+			news.DELETE("/:id/schedule", can(models.PermissionNewsPublish), newsHandler.CancelSchedule)              // Batalkan jadwal, berita terjadwal kembali ke draft
+
+			// Riwayat revisi, setiap simpan menghasilkan revisi baru
+			news.GET("/:id/revisions", can(models.PermissionNewsRead), newsHandler.GetRevisions)          // Daftar revisi (tanpa content)
+			news.GET("/:id/revisions/diff", can(models.PermissionNewsRead), newsHandler.DiffRevisions)    // Bandingkan dua revisi (?from=&to=)
+			news.GET("/:id/revisions/:revision", can(models.PermissionNewsRead), newsHandler.GetRevision) // Detail revisi beserta content
+			news.POST("/:id/revisions/:revision/restore", canAny(models.PermissionNewsEditOwn, models.PermissionNewsEditAny),
+				newsHandler.RestoreRevision) // Kembalikan isi revisi sebagai revisi baru
+
+			// Workflow redaksi, permission tiap transisi dicek sesuai aturan workflow
+			news.GET("/workflow", can(models.PermissionNewsRead), newsWorkflowHandler.GetWorkflow)   // Transisi status yang diizinkan
+			news.POST("/:id/transition", can(models.PermissionNewsRead), newsHandler.Transition)     // Pindah status (status, comment, legal_advice)
+			news.GET("/:id/transitions", can(models.PermissionNewsRead), newsHandler.GetTransitions) // Riwayat status beserta komentar reviewer
+
+			news.GET("/search", can(models.PermissionNewsRead), newsHandler.Search) // Full-text search semua status termasuk draft (?status=)
 		}
 
 		// Member management - CRUD lengkap
 		members := admin.Group("/members")
 		{
-			members.GET("", memberHandler.GetAll)        // Get all members
-			members.GET("/:id", memberHandler.GetByID)   // Get specific member
-			members.POST("", memberHandler.Create)       // Create member
-			members.PUT("/:id", memberHandler.Update)    // Update member
-			members.DELETE("/:id", memberHandler.Delete) // Delete member
+			members.GET("", can(models.PermissionMembersRead), memberHandler.GetAll)          // Get all members
+			members.GET("/:id", can(models.PermissionMembersRead), memberHandler.GetByID)     // Get specific member
+			members.POST("", can(models.PermissionMembersWrite), memberHandler.Create)        // Create member
+			members.PUT("/:id", can(models.PermissionMembersWrite), memberHandler.Update)     // Update member
+			members.DELETE("/:id", can(models.PermissionMembersDelete), memberHandler.Delete) // Delete member
+		}
+
+		// Kategori dan tag berita
+		categories := admin.Group("/categories")
+		{
+			categories.GET("", can(models.PermissionNewsRead), categoryHandler.List)              // Semua kategori, news_count termasuk draft
+			categories.POST("", can(models.PermissionNewsTaxonomy), categoryHandler.Create)       // Buat kategori
+			categories.PUT("/:id", can(models.PermissionNewsTaxonomy), categoryHandler.Update)    // Ubah kategori, nama baru ikut ke berita
+			categories.DELETE("/:id", can(models.PermissionNewsTaxonomy), categoryHandler.Delete) // Hapus kategori (?move_to= jika masih berisi berita)
+		}
+
+		tags := admin.Group("/tags")
+		{
+			tags.GET("", can(models.PermissionNewsRead), tagHandler.List)              // Semua tag, news_count termasuk draft
+			tags.POST("", can(models.PermissionNewsTaxonomy), tagHandler.Create)       // Buat tag
+			tags.PUT("/:id", can(models.PermissionNewsTaxonomy), tagHandler.Update)    // Ubah tag
+			tags.DELETE("/:id", can(models.PermissionNewsTaxonomy), tagHandler.Delete) // Hapus tag dari semua berita
 		}
 	}
 }
@@ -92,16 +190,104 @@ func (a *App) setupAdminRoutes(v1 *gin.RouterGroup) {
 func (a *App) setupSuperAdminRoutes(v1 *gin.RouterGroup) {
 	authService := a.getAuthService()
 	adminHandler := a.getAdminHandler()
+	twoFactorHandler := a.getTwoFactorHandler()
+	lockoutHandler := a.getLockoutHandler()
+	roleHandler := a.getRoleHandler()
+	apiKeyHandler := a.getAPIKeyHandler()
+	auditHandler := a.getAuditHandler()
+	invitationHandler := a.getInvitationHandler()
+	securityEventHandler := a.getSecurityEventHandler()
+	impersonationHandler := a.getImpersonationHandler()
+	ipAccessHandler := a.getIPAccessHandler()
+	newsWorkflowHandler := a.getNewsWorkflowHandler()
+	roleService := a.getRoleService()
 
-	// Super admin routes (only super admin can access)
+	// Super admin routes (default hanya super admin, role lain bisa diberi permission terkait)
 	superAdmin := v1.Group("/super-admin")
-	superAdmin.Use(middleware.AuthMiddleware(authService))
-	superAdmin.Use(middleware.RequireRole(models.SuperAdmin))
+	superAdmin.Use(
+		middleware.IPAccessMiddleware(a.getIPAccessService(), models.IPAccessGroupSuperAdmin),
+		middleware.AuthMiddleware(authService),
+	)
 	{
-		// Admin management - hanya yang tersedia
+		// Admin management - lifecycle lengkap
 		admins := superAdmin.Group("/admins")
+		admins.Use(middleware.RequirePermission(roleService, models.PermissionUsersManage))
+		{
+			admins.GET("", adminHandler.ListAdmins)                // List admins (paginated, searchable)
+			admins.GET("/:id", adminHandler.GetAdmin)              // Get specific admin
+			admins.POST("", adminHandler.CreateAdmin)              // Create new admin
+			admins.PUT("/:id", adminHandler.UpdateAdmin)           // Update username/email
+			admins.POST("/:id/disable", adminHandler.DisableAdmin) // Disable account
+			admins.POST("/:id/enable", adminHandler.EnableAdmin)   // Re-enable account
+			admins.PUT("/:id/role", adminHandler.ChangeRole)       // Change role
+			admins.DELETE("/:id", adminHandler.DeleteAdmin)        // Delete account
+
+			admins.GET("/:id/security-events", securityEventHandler.GetUserSecurityEvents) // Login history and security events
+		}
+
+		// Impersonation: super admin memakai akun admin lain sementara untuk reproduksi masalah.
+		// Stop juga bisa dipanggil dengan token impersonation, yang memakai role admin tersebut.
+		impersonation := superAdmin.Group("/impersonation")
+		{
+			impersonation.POST("/start", middleware.RequirePermission(roleService, models.PermissionUsersManage), impersonationHandler.Start) // Token sementara, request DELETE diblokir kecuali allow_destructive
+			impersonation.POST("/stop", impersonationHandler.Stop)                                                                            // Akhiri sesi ini, atau semua sesi milik super admin
+		}
+
+		// Undangan admin lewat email, yang diundang memilih password sendiri
+		invitations := superAdmin.Group("/invitations")
+		invitations.Use(middleware.RequirePermission(roleService, models.PermissionUsersManage))
+		{
+			invitations.GET("", invitationHandler.ListInvitations)              // List open invitations
+			invitations.POST("", invitationHandler.CreateInvitation)            // Invite by email with a role
+			invitations.POST("/:id/resend", invitationHandler.ResendInvitation) // New link, old link stops working
+			invitations.DELETE("/:id", invitationHandler.RevokeInvitation)      // Revoke pending invitation
+		}
+
+		// Roles & permissions
+		roles := superAdmin.Group("/roles")
+		roles.Use(middleware.RequirePermission(roleService, models.PermissionRolesManage))
+		{
+			roles.GET("", roleHandler.ListRoles)                   // List roles with permissions
+			roles.GET("/permissions", roleHandler.ListPermissions) // List assignable permissions
+			roles.GET("/:name", roleHandler.GetRole)               // Get specific role
+			roles.POST("", roleHandler.CreateRole)                 // Create custom role
+			roles.PUT("/:name", roleHandler.UpdateRole)            // Update role permissions
+			roles.DELETE("/:name", roleHandler.DeleteRole)         // Delete custom role
+		}
+
+		// API keys untuk build server / script (scope = permission)
+		apiKeys := superAdmin.Group("/api-keys")
+		apiKeys.Use(middleware.RequirePermission(roleService, models.PermissionAPIKeysManage))
+		{
+			apiKeys.GET("", apiKeyHandler.ListAPIKeys)         // List keys (tanpa key asli)
+			apiKeys.POST("", apiKeyHandler.CreateAPIKey)       // Create key, ditampilkan sekali
+			apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey) // Revoke key
+		}
+
+		// Audit log, filter: actor_id, entity_type, entity_id, action, from, to
+		superAdmin.GET("/audit-logs", middleware.RequirePermission(roleService, models.PermissionAuditRead), auditHandler.ListAuditLogs)
+
+		// Security settings
+		settings := superAdmin.Group("/settings")
+		settings.Use(middleware.RequirePermission(roleService, models.PermissionSettingsManage))
+		{
+			settings.GET("/two-factor", twoFactorHandler.GetPolicy)    // Get 2FA policy
+			settings.PUT("/two-factor", twoFactorHandler.UpdatePolicy) // Require 2FA for super admins
+			settings.GET("/ip-access", ipAccessHandler.GetRules)       // IP/CIDR and country allowlists per route group
+			settings.PUT("/ip-access", ipAccessHandler.UpdateRules)    // Replace allowlists, refused if it locks the caller out
+			settings.DELETE("/ip-access", ipAccessHandler.ResetRules)  // Back to ADMIN_ALLOWED_* / SUPER_ADMIN_ALLOWED_*
+
+			settings.GET("/news-workflow", newsWorkflowHandler.GetWorkflow)      // Allowed news status changes and their permissions
+			settings.PUT("/news-workflow", newsWorkflowHandler.UpdateWorkflow)   // Replace the allowed status changes
+			settings.DELETE("/news-workflow", newsWorkflowHandler.ResetWorkflow) // Back to the default workflow
+		}
+
+		// Login lockouts (per username / per IP)
+		lockouts := superAdmin.Group("/lockouts")
+		lockouts.Use(middleware.RequirePermission(roleService, models.PermissionSettingsManage))
 		{
-			admins.POST("", adminHandler.CreateAdmin) // Create new admin
+			lockouts.GET("", lockoutHandler.ListLockouts)                  // List active lockouts
+			lockouts.DELETE("/:scope/:value", lockoutHandler.ClearLockout) // Clear lockout, scope: username | ip
 		}
 	}
 }
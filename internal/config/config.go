@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/driver/mysql"
@@ -12,9 +13,25 @@ import (
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	JWT      JWTConfig
+	Database      DatabaseConfig
+	Server        ServerConfig
+	JWT           JWTConfig
+	Mail          MailConfig
+	PasswordReset PasswordResetConfig
+	Invitation    InvitationConfig
+	TwoFactor     TwoFactorConfig
+	LoginThrottle LoginThrottleConfig
+	Revocation    RevocationConfig
+	Password      PasswordPolicyConfig
+	Bootstrap     BootstrapConfig
+	SSO           SSOConfig
+	WebAuthn      WebAuthnConfig
+	Cookie        CookieConfig
+	CORS          CORSConfig
+	Impersonation ImpersonationConfig
+	Network       NetworkConfig
+	News          NewsConfig
+	Role          RoleConfig
 }
 
 type DatabaseConfig struct {
@@ -37,6 +54,128 @@ type ServerConfig struct {
 type JWTConfig struct {
 	SecretKey      string
 	ExpirationTime time.Duration
+	Algorithm      string // HS256 (default), RS256 atau EdDSA
+	KeysDir        string // Folder key pair untuk RS256/EdDSA
+	ActiveKeyID    string // kid untuk menandatangani token baru, default kid terbaru di KeysDir
+}
+
+type MailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type PasswordResetConfig struct {
+	URL string // Halaman frontend untuk reset password, token ditambahkan sebagai query ?token=
+	TTL time.Duration
+}
+
+type InvitationConfig struct {
+	URL string // Halaman frontend untuk menerima undangan admin, token ditambahkan sebagai query ?token=
+	TTL time.Duration
+}
+
+type TwoFactorConfig struct {
+	Issuer string // Nama yang tampil di aplikasi authenticator
+}
+
+type LoginThrottleConfig struct {
+	MaxAttempts     int           // Login gagal per username sebelum dikunci sementara
+	MaxAttemptsIP   int           // Login gagal per IP sebelum dikunci sementara
+	FreeAttempts    int           // Login gagal sebelum progressive delay dimulai
+	BaseDelay       time.Duration // Delay pertama, berlipat dua setiap kegagalan berikutnya
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Window          time.Duration // Kegagalan yang lebih lama dari ini tidak dihitung lagi
+}
+
+type RevocationConfig struct {
+	SyncInterval           time.Duration // Seberapa sering cache token yang dicabut memuat pencabutan dari instance lain
+	CleanupInterval        time.Duration // Seberapa sering token blacklist, sesi dan token reset yang expire dihapus
+	SecurityEventRetention time.Duration // Lama riwayat login/keamanan disimpan, 0 = selamanya
+}
+
+type PasswordPolicyConfig struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	HistorySize      int    // Jumlah password terakhir yang tidak boleh dipakai ulang, 0 = nonaktif
+	BreachedListPath string // File hash SHA-1 password yang bocor (format HASH atau HASH:COUNT per baris)
+}
+
+// BootstrapConfig membuat super admin pertama dari env. Jika kosong, setup token sekali pakai dicetak ke log.
+type BootstrapConfig struct {
+	AdminUsername string
+	AdminEmail    string
+	AdminPassword string // Harus diganti saat login pertama
+}
+
+// SSOConfig mengaktifkan login lewat OpenID Connect jika Issuer diisi
+type SSOConfig struct {
+	Issuer               string
+	ClientID             string
+	ClientSecret         string
+	RedirectURL          string // Callback API ini: /api/v1/auth/sso/callback
+	Scopes               []string
+	PostLoginURL         string // Halaman frontend setelah login SSO, ambil access token lewat /auth/refresh
+	AutoProvision        bool   // Buat user baru jika email belum terdaftar
+	DefaultRole          string // Role untuk user yang dibuat otomatis
+	RequireVerifiedEmail bool
+	PasswordLoginEnabled bool // false = hanya SSO (diabaikan jika SSO belum dikonfigurasi)
+}
+
+// WebAuthnConfig mengatur passkey. RPID harus domain frontend (atau parent domain-nya).
+type WebAuthnConfig struct {
+	RPID        string
+	RPName      string
+	Origins     []string // Origin frontend yang boleh membuat dan memakai passkey
+	Attestation string   // none, indirect atau direct
+}
+
+// CookieConfig mengatur cookie refresh token dan CSRF
+type CookieConfig struct {
+	Secure     bool   // Wajib true di production (HTTPS)
+	SameSite   string // lax, strict atau none (none otomatis Secure)
+	Domain     string // Kosong = hanya host API
+	Path       string
+	HostPrefix bool // Pakai prefix __Host- (Secure, Path=/, tanpa Domain)
+}
+
+// CORSConfig berisi origin frontend yang boleh memanggil API dengan cookie
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+// ImpersonationConfig membatasi lama sesi super admin yang menyamar sebagai admin lain
+type ImpersonationConfig struct {
+	MaxTTL time.Duration
+}
+
+// NetworkConfig mengatur proxy yang dipercaya untuk IP client dan batasan asal request
+// ke route admin. Aturan IP bisa diubah lewat API, env hanya nilai awal.
+type NetworkConfig struct {
+	TrustedProxies             []string // IP/CIDR reverse proxy (nginx) yang boleh mengisi X-Forwarded-For
+	CountryHeader              string   // Header kode negara dari proxy/CDN, mis. CF-IPCountry. Kosong = geo-fencing nonaktif
+	AdminAllowedIPs            []string
+	AdminAllowedCountries      []string
+	SuperAdminAllowedIPs       []string
+	SuperAdminAllowedCountries []string
+
+	IPAccessSyncInterval time.Duration // Seberapa sering aturan IP yang diubah lewat API dimuat ulang, 0 = nonaktif
+}
+
+// NewsConfig mengatur worker yang mempublish berita terjadwal dan mengarsipkan berita yang expire
+type NewsConfig struct {
+	SchedulerInterval    time.Duration // 0 = worker nonaktif (halaman publik tetap mengikuti jadwal)
+	WorkflowSyncInterval time.Duration // Seberapa sering workflow yang diubah lewat API dimuat ulang, 0 = nonaktif
+}
+
+type RoleConfig struct {
+	SyncInterval time.Duration // Seberapa sering permission role yang diubah lewat API dimuat ulang, 0 = nonaktif
 }
 
 func LoadConfig() *Config {
@@ -59,6 +198,102 @@ func LoadConfig() *Config {
 		JWT: JWTConfig{
 			SecretKey:      getEnv("JWT_SECRET", "your-secret-key"),
 			ExpirationTime: getEnvAsDuration("JWT_EXPIRATION", 15*time.Minute),
+			Algorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+			KeysDir:        getEnv("JWT_KEYS_DIR", "./keys"),
+			ActiveKeyID:    getEnv("JWT_ACTIVE_KEY_ID", ""),
+		},
+		Mail: MailConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "1025"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("MAIL_FROM", "no-reply@haslaw.com"),
+		},
+		PasswordReset: PasswordResetConfig{
+			URL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			TTL: getEnvAsDuration("PASSWORD_RESET_TTL", 30*time.Minute),
+		},
+		Invitation: InvitationConfig{
+			URL: getEnv("INVITATION_URL", "http://localhost:3000/accept-invitation"),
+			TTL: getEnvAsDuration("INVITATION_TTL", 72*time.Hour),
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer: getEnv("TOTP_ISSUER", "Haslaw"),
+		},
+		LoginThrottle: LoginThrottleConfig{
+			MaxAttempts:     getEnvAsInt("LOGIN_MAX_ATTEMPTS", 10),
+			MaxAttemptsIP:   getEnvAsInt("LOGIN_MAX_ATTEMPTS_PER_IP", 30),
+			FreeAttempts:    getEnvAsInt("LOGIN_FREE_ATTEMPTS", 3),
+			BaseDelay:       getEnvAsDuration("LOGIN_BASE_DELAY", 2*time.Second),
+			MaxDelay:        getEnvAsDuration("LOGIN_MAX_DELAY", 60*time.Second),
+			LockoutDuration: getEnvAsDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			Window:          getEnvAsDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		},
+		Revocation: RevocationConfig{
+			SyncInterval:           getEnvAsDuration("TOKEN_REVOCATION_SYNC_INTERVAL", 30*time.Second),
+			CleanupInterval:        getEnvAsDuration("TOKEN_CLEANUP_INTERVAL", time.Hour),
+			SecurityEventRetention: getEnvAsDuration("SECURITY_EVENT_RETENTION", 180*24*time.Hour),
+		},
+		Password: PasswordPolicyConfig{
+			MinLength:        getEnvAsInt("PASSWORD_MIN_LENGTH", 12),
+			RequireUppercase: getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", true),
+			RequireLowercase: getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", true),
+			RequireDigit:     getEnvAsBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:    getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			HistorySize:      getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+			BreachedListPath: getEnv("PASSWORD_BREACHED_LIST", ""),
+		},
+		Bootstrap: BootstrapConfig{
+			AdminUsername: getEnv("BOOTSTRAP_ADMIN_USERNAME", ""),
+			AdminEmail:    getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+			AdminPassword: getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+		},
+		SSO: SSOConfig{
+			Issuer:               getEnv("OIDC_ISSUER", ""),
+			ClientID:             getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:         getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:          getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/v1/auth/sso/callback"),
+			Scopes:               strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+			PostLoginURL:         getEnv("OIDC_POST_LOGIN_URL", "http://localhost:3000/login/sso"),
+			AutoProvision:        getEnvAsBool("OIDC_AUTO_PROVISION", false),
+			DefaultRole:          getEnv("OIDC_DEFAULT_ROLE", "author"),
+			RequireVerifiedEmail: getEnvAsBool("OIDC_REQUIRE_VERIFIED_EMAIL", true),
+			PasswordLoginEnabled: getEnvAsBool("PASSWORD_LOGIN_ENABLED", true),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:        getEnv("WEBAUTHN_RP_ID", "localhost"),
+			RPName:      getEnv("WEBAUTHN_RP_NAME", "Haslaw"),
+			Origins:     getEnvAsList("WEBAUTHN_ORIGINS", "http://localhost:3000"),
+			Attestation: getEnv("WEBAUTHN_ATTESTATION", "direct"),
+		},
+		Cookie: CookieConfig{
+			Secure:     getEnvAsBool("COOKIE_SECURE", false),
+			SameSite:   getEnv("COOKIE_SAMESITE", "lax"),
+			Domain:     getEnv("COOKIE_DOMAIN", ""),
+			Path:       getEnv("COOKIE_PATH", "/api/v1/auth"),
+			HostPrefix: getEnvAsBool("COOKIE_HOST_PREFIX", false),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvAsList("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
+		},
+		Impersonation: ImpersonationConfig{
+			MaxTTL: getEnvAsDuration("IMPERSONATION_TTL", 30*time.Minute),
+		},
+		Network: NetworkConfig{
+			TrustedProxies:             getEnvAsList("TRUSTED_PROXIES", "127.0.0.1,::1"),
+			CountryHeader:              getEnv("GEO_COUNTRY_HEADER", ""),
+			AdminAllowedIPs:            getEnvAsList("ADMIN_ALLOWED_IPS", ""),
+			AdminAllowedCountries:      getEnvAsList("ADMIN_ALLOWED_COUNTRIES", ""),
+			SuperAdminAllowedIPs:       getEnvAsList("SUPER_ADMIN_ALLOWED_IPS", ""),
+			SuperAdminAllowedCountries: getEnvAsList("SUPER_ADMIN_ALLOWED_COUNTRIES", ""),
+			IPAccessSyncInterval:       getEnvAsDuration("IP_ACCESS_SYNC_INTERVAL", 30*time.Second),
+		},
+		News: NewsConfig{
+			SchedulerInterval:    getEnvAsDuration("NEWS_SCHEDULER_INTERVAL", 30*time.Second),
+			WorkflowSyncInterval: getEnvAsDuration("NEWS_WORKFLOW_SYNC_INTERVAL", 30*time.Second),
+		},
+		Role: RoleConfig{
+			SyncInterval: getEnvAsDuration("ROLE_SYNC_INTERVAL", 30*time.Second),
 		},
 	}
 }
@@ -126,3 +361,23 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList membaca daftar yang dipisahkan koma, mis. "https://a.com, https://b.com"
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/repository"
 	"haslaw-be-services/internal/service"
@@ -12,14 +13,20 @@ import (
 )
 
 type AdminHandler struct {
-	authService service.AuthService
-	userRepo    repository.UserRepository
+	authService          service.AuthService
+	userService          service.UserService
+	userRepo             repository.UserRepository
+	auditService         service.AuditService
+	securityEventService service.SecurityEventService
 }
 
-func NewAdminHandler(authService service.AuthService, userRepo repository.UserRepository) *AdminHandler {
+func NewAdminHandler(authService service.AuthService, userService service.UserService, userRepo repository.UserRepository, auditService service.AuditService, securityEventService service.SecurityEventService) *AdminHandler {
 	return &AdminHandler{
-		authService: authService,
-		userRepo:    userRepo,
+		authService:          authService,
+		userService:          userService,
+		userRepo:             userRepo,
+		auditService:         auditService,
+		securityEventService: securityEventService,
 	}
 }
 
@@ -30,20 +37,22 @@ func (h *AdminHandler) CreateAdmin(c *gin.Context) {
 		return
 	}
 
-	admin, err := h.authService.CreateAdmin(&request)
+	admin, err := h.authService.CreateAdmin(c.GetUint("user_id"), &request)
 	if err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You cannot manage accounts or assign roles with permissions you do not have")
+			return
+		}
 		utils.BadRequestResponse(c, "Failed to create admin", err.Error())
 		return
 	}
 
-	response := models.UserResponse{
-		ID:       admin.ID,
-		Username: admin.Username,
-		Email:    admin.Email,
-		Role:     admin.Role,
-	}
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityUser, admin.ID, nil, admin.ToResponse())
 
-	utils.SuccessResponse(c, http.StatusCreated, "Admin created successfully", response)
+	utils.SuccessResponse(c, http.StatusCreated, "Admin created successfully", admin.ToResponse())
 }
 
 func (h *AdminHandler) UpdateProfile(c *gin.Context) {
@@ -59,20 +68,36 @@ func (h *AdminHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	before, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+
 	user, err := h.authService.UpdateProfile(userID.(uint), &request)
 	if err != nil {
+		recordPasswordChangeFailure(c, h.securityEventService, err)
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
 		utils.BadRequestResponse(c, "Failed to update profile", err.Error())
 		return
 	}
 
-	response := models.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     user.Role,
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityUser, user.ID, before.ToResponse(), user.ToResponse())
+
+	// Password changes revoke every token, including the one used for this request
+	if request.Password != "" {
+		event := newSecurityEvent(c, models.SecurityEventPasswordChange, true, 0, "")
+		event.Method = securityMethodPassword
+		h.securityEventService.Record(event)
+
+		utils.ClearRefreshTokenCookie(c)
+		utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully, please log in again", user.ToResponse())
+		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", response)
+	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", user.ToResponse())
 }
 
 func (h *AdminHandler) GetProfile(c *gin.Context) {
@@ -109,12 +134,187 @@ func (h *AdminHandler) GetProfile(c *gin.Context) {
 	}
 
 	// Return user profile with email
-	response := models.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     user.Role,
+	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", user.ToResponse())
+}
+
+// ListAdmins lists admin accounts with pagination and optional search
+func (h *AdminHandler) ListAdmins(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+	role := models.UserRole(c.Query("role"))
+
+	users, meta, err := h.userService.ListAdmins(page, limit, search, role)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch admins", err.Error())
+		return
+	}
+
+	responses := make([]models.UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, users[i].ToResponse())
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", response)
+	utils.SuccessWithPagination(c, "Admins retrieved successfully", responses, *meta)
+}
+
+// GetAdmin gets a single admin account by ID
+func (h *AdminHandler) GetAdmin(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetAdmin(id)
+	if err != nil {
+		h.handleUserError(c, "Failed to fetch admin", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Admin retrieved successfully", user.ToResponse())
+}
+
+// UpdateAdmin updates username and email of an admin account
+func (h *AdminHandler) UpdateAdmin(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var request models.UpdateAdminRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, ok := h.snapshot(c, id)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.UpdateAdmin(c.GetUint("user_id"), id, &request)
+	if err != nil {
+		h.handleUserError(c, "Failed to update admin", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityUser, id, before, user.ToResponse())
+
+	utils.SuccessResponse(c, http.StatusOK, "Admin updated successfully", user.ToResponse())
+}
+
+// DisableAdmin disables an admin account so it can no longer log in
+func (h *AdminHandler) DisableAdmin(c *gin.Context) {
+	h.setActive(c, false, "Admin disabled successfully")
+}
+
+// EnableAdmin re-enables a disabled admin account
+func (h *AdminHandler) EnableAdmin(c *gin.Context) {
+	h.setActive(c, true, "Admin enabled successfully")
+}
+
+// ChangeRole changes the role of an admin account
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var request models.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, ok := h.snapshot(c, id)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.ChangeRole(c.GetUint("user_id"), id, request.Role)
+	if err != nil {
+		h.handleUserError(c, "Failed to change role", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityUser, id, before, user.ToResponse())
+
+	utils.SuccessResponse(c, http.StatusOK, "Role changed successfully", user.ToResponse())
+}
+
+// DeleteAdmin permanently removes an admin account
+func (h *AdminHandler) DeleteAdmin(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	before, ok := h.snapshot(c, id)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DeleteAdmin(c.GetUint("user_id"), id); err != nil {
+		h.handleUserError(c, "Failed to delete admin", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityUser, id, before, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Admin deleted successfully", nil)
+}
+
+func (h *AdminHandler) setActive(c *gin.Context, active bool, message string) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	before, ok := h.snapshot(c, id)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.SetActive(c.GetUint("user_id"), id, active)
+	if err != nil {
+		h.handleUserError(c, "Failed to change admin status", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityUser, id, before, user.ToResponse())
+
+	utils.SuccessResponse(c, http.StatusOK, message, user.ToResponse())
+}
+
+// snapshot loads the account as it is before a change, for the audit log diff
+func (h *AdminHandler) snapshot(c *gin.Context, id uint) (*models.UserResponse, bool) {
+	user, err := h.userService.GetAdmin(id)
+	if err != nil {
+		h.handleUserError(c, "Failed to fetch admin", err)
+		return nil, false
+	}
+	response := user.ToResponse()
+	return &response, true
+}
+
+func (h *AdminHandler) handleUserError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		utils.NotFoundResponse(c, "User not found")
+	case errors.Is(err, service.ErrForbidden):
+		utils.ForbiddenResponse(c, "You cannot manage accounts or assign roles with permissions you do not have")
+	case errors.Is(err, service.ErrLastSuperAdmin), errors.Is(err, service.ErrSelfModification):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
+
+func parseUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
 }
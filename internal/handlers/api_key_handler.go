@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles API key management
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	auditService  service.AuditService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService, auditService service.AuditService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		auditService:  auditService,
+	}
+}
+
+// ListAPIKeys lists every API key without the key itself
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.List()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch API keys", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+// CreateAPIKey creates a key and returns it once
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var request models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.GetUint("user_id"), models.UserRole(c.GetString("role")), &request)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You cannot grant scopes you do not have")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to create API key", err.Error())
+		return
+	}
+
+	// Snapshot tanpa key asli
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityAPIKey, key.ID, nil, key.APIKey)
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created. Copy it now, it will not be shown again", key)
+}
+
+// RevokeAPIKey revokes a key immediately
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid API key ID", err.Error())
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(uint(id)); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			utils.NotFoundResponse(c, "API key not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to revoke API key", err.Error())
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityAPIKey, uint(id), nil, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Entity types used in audit log entries
+const (
+	auditEntityNews       = "news"
+	auditEntityMember     = "member"
+	auditEntityUser       = "user"
+	auditEntityRole       = "role"
+	auditEntityAPIKey     = "api_key"
+	auditEntityInvitation = "invitation"
+	auditEntityPasskey    = "passkey"
+	auditEntitySession    = "session"
+	auditEntitySetting    = "setting" // Entity ID is the setting key
+	auditEntityCategory   = "category"
+	auditEntityTag        = "tag"
+)
+
+// AuditHandler exposes the audit log to super admins
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLogs lists audit entries, newest first. Filters: actor_id, impersonator_id, entity_type,
+// entity_id, action, from and to (YYYY-MM-DD or RFC 3339, a date-only "to" is inclusive).
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filter := models.AuditLogFilter{
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Action:     models.AuditAction(c.Query("action")),
+	}
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid actor_id", err.Error())
+			return
+		}
+		filter.ActorID = uint(id)
+	}
+
+	if impersonatorID := c.Query("impersonator_id"); impersonatorID != "" {
+		id, err := strconv.ParseUint(impersonatorID, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid impersonator_id", err.Error())
+			return
+		}
+		filter.ImpersonatorID = uint(id)
+	}
+
+	if filter.Action != "" && !filter.Action.IsValid() {
+		utils.BadRequestResponse(c, "Invalid action", fmt.Sprintf("action must be one of %v", models.ValidAuditActions))
+		return
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		utils.BadRequestResponse(c, "Invalid from date", err.Error())
+		return
+	}
+	if filter.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		utils.BadRequestResponse(c, "Invalid to date", err.Error())
+		return
+	}
+
+	entries, meta, err := h.auditService.Query(&filter, page, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch audit logs", err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Audit logs retrieved successfully", entries, *meta)
+}
+
+func parseAuditTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("use YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// newAuditEntry fills the actor, trace ID and client of the current request
+func newAuditEntry(c *gin.Context, action models.AuditAction, entityType, entityID string) *models.AuditLog {
+	entry := &models.AuditLog{
+		ActorUsername: c.GetString("username"),
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      entityID,
+		TraceID:       utils.GetTraceID(c),
+		IPAddress:     c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+	}
+
+	if userID := c.GetUint("user_id"); userID != 0 {
+		entry.ActorID = &userID
+	}
+	if apiKeyID := c.GetUint("api_key_id"); apiKeyID != 0 {
+		entry.APIKeyID = &apiKeyID
+	}
+	if impersonatorID := c.GetUint("impersonator_id"); impersonatorID != 0 {
+		entry.ImpersonatorID = &impersonatorID
+		entry.ImpersonatorUsername = c.GetString("impersonator_username")
+	}
+
+	return entry
+}
+
+// recordAudit records a mutation of the entity. Pass nil as before for creates and as after for deletes.
+func recordAudit(c *gin.Context, auditService service.AuditService, action models.AuditAction, entityType string, entityID uint, before, after interface{}) {
+	auditService.Record(newAuditEntry(c, action, entityType, strconv.FormatUint(uint64(entityID), 10)), before, after)
+}
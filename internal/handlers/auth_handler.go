@@ -1,23 +1,45 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/service"
 	"haslaw-be-services/internal/utils"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AuthHandler untuk menghandle permintaan autentikasi
 type AuthHandler struct {
-	authService service.AuthService
+	authService          service.AuthService
+	passwordResetService service.PasswordResetService
+	twoFactorService     service.TwoFactorService
+	auditService         service.AuditService
+	securityEventService service.SecurityEventService
+	passwordPolicy       service.PasswordPolicy
 }
 
 // NewAuthHandler membuat auth handler baru
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
+func NewAuthHandler(
+	authService service.AuthService,
+	passwordResetService service.PasswordResetService,
+	twoFactorService service.TwoFactorService,
+	auditService service.AuditService,
+	securityEventService service.SecurityEventService,
+	passwordPolicy service.PasswordPolicy,
+) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:          authService,
+		passwordResetService: passwordResetService,
+		twoFactorService:     twoFactorService,
+		auditService:         auditService,
+		securityEventService: securityEventService,
+		passwordPolicy:       passwordPolicy,
 	}
 }
 
@@ -28,11 +50,35 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	AccessToken string      `json:"access_token"`
-	TokenType   string      `json:"token_type"`
-	ExpiresIn   int         `json:"expires_in"`
-	User        interface{} `json:"user"`
-	Message     string      `json:"message"`
+	AccessToken            string      `json:"access_token"`
+	TokenType              string      `json:"token_type"`
+	ExpiresIn              int         `json:"expires_in"`
+	User                   interface{} `json:"user"`
+	CSRFToken              string      `json:"csrf_token"` // Kirim di header X-CSRF-Token saat refresh dan logout
+	Message                string      `json:"message"`
+	TwoFactorSetupRequired bool        `json:"two_factor_setup_required,omitempty"`
+	PasswordChangeRequired bool        `json:"password_change_required,omitempty"`
+}
+
+// TwoFactorChallengeResponse dikirim jika akun memakai 2FA dan faktor kedua masih dibutuhkan.
+// Methods berisi "totp" dan/atau "passkey", sesuai yang sudah diaktifkan user.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool     `json:"two_factor_required"`
+	ChallengeToken    string   `json:"challenge_token"`
+	ExpiresIn         int      `json:"expires_in"`
+	Methods           []string `json:"methods"`
+}
+
+// twoFactorMethods mengembalikan faktor kedua yang bisa dipakai user untuk menyelesaikan login
+func twoFactorMethods(user *models.User) []string {
+	methods := []string{}
+	if user.TOTPEnabled {
+		methods = append(methods, "totp")
+	}
+	if user.PasskeyEnabled {
+		methods = append(methods, "passkey")
+	}
+	return methods
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -43,77 +89,219 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, accessToken, refreshToken, err := h.authService.Login(req.Username, req.Password)
+	result, err := h.authService.Login(req.Username, req.Password, clientInfo(c))
 	if err != nil {
-		utils.UnauthorizedResponse(c, "Login gagal")
+		h.recordLogin(c, models.AuditActionLoginFailed, 0, req.Username)
+		recordLoginEvent(c, h.securityEventService, securityMethodPassword, 0, req.Username, err)
+		respondLoginError(c, fmt.Sprintf("login as '%s'", req.Username), err, "Login gagal")
 		return
 	}
 
-	c.SetCookie(
-		"refresh_token",
-		refreshToken,
-		7*24*60*60,
-		"/",
-		"",
-		false,
-		true,
-	)
+	if result.TwoFactorRequired {
+		utils.SuccessResponse(c, http.StatusOK, "Masukkan kode autentikasi dua faktor", TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+			ExpiresIn:         300,
+			Methods:           twoFactorMethods(result.User),
+		})
+		return
+	}
+
+	h.recordLogin(c, models.AuditActionLogin, result.User.ID, result.User.Username)
+	recordLoginEvent(c, h.securityEventService, securityMethodPassword, result.User.ID, result.User.Username, nil)
+	respondWithLogin(c, result, "Login berhasil")
+}
+
+// LoginTwoFactor menyelesaikan login dengan kode TOTP atau recovery code
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Data request tidak valid", err.Error())
+		return
+	}
+
+	result, err := h.twoFactorService.VerifyLogin(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		h.recordLogin(c, models.AuditActionLoginFailed, 0, "")
+		// Password sudah benar tapi faktor kedua salah, tanda kuat akun sedang dibobol
+		userID, username := challengeUser(req.ChallengeToken)
+		recordLoginEvent(c, h.securityEventService, securityMethodTOTP, userID, username, err)
+		respondLoginError(c, "login two-factor verification", err, "Verifikasi dua faktor gagal")
+		return
+	}
+
+	h.recordLogin(c, models.AuditActionLogin, result.User.ID, result.User.Username)
+	recordLoginEvent(c, h.securityEventService, securityMethodTOTP, result.User.ID, result.User.Username, nil)
+	respondWithLogin(c, result, "Login berhasil")
+}
+
+// challengeUser mengambil user dari challenge token 2FA, untuk mencatat verifikasi yang gagal
+func challengeUser(challengeToken string) (uint, string) {
+	claims, err := utils.ValidateChallengeToken(challengeToken, utils.PurposeTwoFactorChallenge)
+	if err != nil {
+		return 0, ""
+	}
+	return claims.UserID, claims.Username
+}
+
+func (h *AuthHandler) recordLogin(c *gin.Context, action models.AuditAction, userID uint, username string) {
+	recordLoginAudit(c, h.auditService, action, userID, username)
+}
+
+// recordLoginAudit mencatat login ke audit log. userID 0 untuk login yang gagal.
+func recordLoginAudit(c *gin.Context, auditService service.AuditService, action models.AuditAction, userID uint, username string) {
+	entry := newAuditEntry(c, action, auditEntityUser, "")
+	entry.ActorUsername = username
+	if userID != 0 {
+		entry.ActorID = &userID
+		entry.EntityID = strconv.FormatUint(uint64(userID), 10)
+	}
+	auditService.Record(entry, nil, nil)
+}
+
+// respondLoginError mencatat login yang gagal dan mengirim 429 jika username/IP sedang di-throttle
+func respondLoginError(c *gin.Context, action string, err error, message string) {
+	utils.NewLogger(c).AuthError(action, err.Error())
+
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		utils.ErrorResponse(c, http.StatusTooManyRequests, "Terlalu banyak percobaan login yang gagal, coba lagi nanti", err.Error())
+		return
+	}
+
+	utils.UnauthorizedResponse(c, message)
+}
+
+// respondPasswordPolicyError mengirim daftar aturan password yang dilanggar, agar frontend
+// bisa menampilkan setiap aturan. Mengembalikan false jika err bukan pelanggaran policy.
+func respondPasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *service.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Password tidak memenuhi kebijakan password", policyErr.Violations)
+		return true
+	}
+
+	if errors.Is(err, service.ErrInvalidCurrentPassword) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Password saat ini salah", []service.PasswordViolation{
+			{Rule: "current_password", Message: err.Error()},
+		})
+		return true
+	}
+
+	return false
+}
+
+// GetPasswordPolicy menampilkan aturan password agar frontend bisa memvalidasi sebelum submit
+func (h *AuthHandler) GetPasswordPolicy(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Kebijakan password berhasil diambil", h.passwordPolicy.Rules())
+}
+
+// setRefreshTokenCookie menyimpan refresh token di cookie httpOnly beserta cookie CSRF,
+// lalu mengembalikan CSRF token yang harus dikirim ulang di header X-CSRF-Token
+func setRefreshTokenCookie(c *gin.Context, refreshToken string) (string, bool) {
+	csrfToken, err := utils.SetRefreshTokenCookie(c, refreshToken)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Gagal menyimpan refresh token", err.Error())
+		return "", false
+	}
+	return csrfToken, true
+}
+
+// respondWithLogin menyimpan refresh token di cookie dan mengirim access token
+func respondWithLogin(c *gin.Context, result *service.LoginResult, message string) {
+	csrfToken, ok := setRefreshTokenCookie(c, result.RefreshToken)
+	if !ok {
+		return
+	}
 
 	// Buat response login (tanpa refresh token di body, karena sudah di cookie)
 	response := LoginResponse{
-		AccessToken: accessToken,
+		AccessToken: result.AccessToken,
 		TokenType:   "Bearer",
 		ExpiresIn:   900, // 15 menit untuk access token
 		User: map[string]interface{}{
-			"id":       user.ID,
-			"username": user.Username,
-			"email":    user.Email,
-			"role":     user.Role,
+			"id":                   result.User.ID,
+			"username":             result.User.Username,
+			"email":                result.User.Email,
+			"role":                 result.User.Role,
+			"two_factor_enabled":   result.User.TOTPEnabled,
+			"passkey_enabled":      result.User.PasskeyEnabled,
+			"must_change_password": result.User.MustChangePassword,
 		},
-		Message: "Refresh token tersimpan di cookie (7 hari)",
+		CSRFToken:              csrfToken,
+		Message:                "Refresh token tersimpan di cookie (7 hari)",
+		TwoFactorSetupRequired: result.TwoFactorSetupRequired,
+		PasswordChangeRequired: result.PasswordChangeRequired,
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Login berhasil", response)
+	utils.SuccessResponse(c, http.StatusOK, message, response)
 }
 
 // RefreshToken untuk memperbarui token
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// Ambil refresh token dari cookie
-	refreshToken, err := c.Cookie("refresh_token")
+	refreshToken, err := utils.RefreshTokenFromCookie(c)
 	if err != nil {
 		utils.UnauthorizedResponse(c, "Refresh token tidak ditemukan di cookie")
 		return
 	}
 
 	// Proses refresh token
-	newAccessToken, newRefreshToken, err := h.authService.RefreshToken(refreshToken)
+	newAccessToken, newRefreshToken, err := h.authService.RefreshToken(refreshToken, clientInfo(c))
+	h.recordRefresh(c, refreshToken, err)
 	if err != nil {
 		// Hapus cookie jika refresh token tidak valid
-		c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+		utils.ClearRefreshTokenCookie(c)
 		utils.UnauthorizedResponse(c, "Refresh token tidak valid atau expired")
 		return
 	}
 
-	c.SetCookie(
-		"refresh_token",
-		newRefreshToken,
-		7*24*60*60,
-		"/",
-		"",
-		false,
-		true,
-	)
+	csrfToken, ok := setRefreshTokenCookie(c, newRefreshToken)
+	if !ok {
+		return
+	}
 
 	response := map[string]interface{}{
 		"access_token": newAccessToken,
 		"token_type":   "Bearer",
 		"expires_in":   900,
+		"csrf_token":   csrfToken,
 		"message":      "Token berhasil diperbarui, refresh token baru tersimpan di cookie",
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Token berhasil diperbarui", response)
 }
 
+// CSRFToken mengembalikan CSRF token untuk header X-CSRF-Token, mis. setelah login SSO
+// atau reload halaman. Hanya origin di CORS allowlist yang bisa membaca response ini.
+func (h *AuthHandler) CSRFToken(c *gin.Context) {
+	csrfToken, err := utils.IssueCSRFToken(c)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Gagal membuat CSRF token", err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	utils.SuccessResponse(c, http.StatusOK, "CSRF token berhasil diambil", gin.H{"csrf_token": csrfToken})
+}
+
+// recordRefresh mencatat refresh token di riwayat keamanan user, termasuk pemakaian ulang
+// refresh token lama. User diambil dari token hanya untuk pencatatan, validasi tetap di service.
+func (h *AuthHandler) recordRefresh(c *gin.Context, refreshToken string, err error) {
+	claims, parseErr := utils.ValidateRefreshToken(refreshToken)
+	if parseErr != nil {
+		return
+	}
+
+	event := newSecurityEvent(c, models.SecurityEventTokenRefresh, err == nil, claims.UserID, claims.Username)
+	if err != nil {
+		event.Detail = err.Error()
+	}
+	h.securityEventService.Record(event)
+}
+
 func (h *AuthHandler) Logout(c *gin.Context) {
 
 	userID, exists := c.Get("user_id")
@@ -128,7 +316,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	refreshToken, err := c.Cookie("refresh_token")
+	refreshToken, err := utils.RefreshTokenFromCookie(c)
 	if err != nil {
 
 		log.Printf("Warning: No refresh token found in cookie during logout: %v", err)
@@ -143,15 +331,158 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		log.Printf("Refresh token removed from cookie during logout")
 	}
 
-	c.SetCookie(
-		"refresh_token",
-		"",
-		-1,
-		"/",
-		"",
-		false,
-		true,
-	)
+	recordAudit(c, h.auditService, models.AuditActionLogout, auditEntityUser, userID.(uint), nil, nil)
+	h.securityEventService.Record(newSecurityEvent(c, models.SecurityEventLogout, true, 0, ""))
+
+	utils.ClearRefreshTokenCookie(c)
 
 	utils.SuccessResponse(c, http.StatusOK, "Logout berhasil, semua token telah dihapus", nil)
 }
+
+// ForgotPassword mengirim email berisi tautan reset password
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Data request tidak valid", err.Error())
+		return
+	}
+
+	if err := h.passwordResetService.ForgotPassword(req.Email); err != nil {
+		utils.InternalServerErrorResponse(c, "Gagal memproses permintaan reset password", err.Error())
+		return
+	}
+
+	// Response selalu sama agar email terdaftar tidak bisa ditebak
+	utils.SuccessResponse(c, http.StatusOK, "Jika email terdaftar, tautan reset password telah dikirim", nil)
+}
+
+// ResetPassword mengganti password menggunakan token reset
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Data request tidak valid", err.Error())
+		return
+	}
+
+	user, err := h.passwordResetService.ResetPassword(req.Token, req.NewPassword)
+	if err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			utils.BadRequestResponse(c, "Reset password gagal", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Reset password gagal", err.Error())
+		return
+	}
+
+	event := newSecurityEvent(c, models.SecurityEventPasswordChange, true, user.ID, user.Username)
+	event.Method = securityMethodReset
+	h.securityEventService.Record(event)
+
+	utils.ClearRefreshTokenCookie(c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Password berhasil direset, silakan login kembali", nil)
+}
+
+// ChangePassword mengganti password user yang sedang login. Semua token lama dicabut
+// dan user langsung mendapat sesi baru tanpa perlu login ulang.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Data request tidak valid", err.Error())
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	result, err := h.authService.ChangePassword(userID, &req, clientInfo(c))
+	if err != nil {
+		recordPasswordChangeFailure(c, h.securityEventService, err)
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Ganti password gagal", err.Error())
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityUser, userID, nil, nil)
+	event := newSecurityEvent(c, models.SecurityEventPasswordChange, true, 0, "")
+	event.Method = securityMethodPassword
+	h.securityEventService.Record(event)
+
+	respondWithLogin(c, result, "Password berhasil diganti")
+}
+
+// ListSessions menampilkan semua sesi login aktif milik user
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.authService.ListSessions(c.GetUint("user_id"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Gagal mengambil daftar sesi", err.Error())
+		return
+	}
+
+	currentSessionID := c.GetString("session_id")
+	responses := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, models.SessionResponse{
+			ID:             session.ID,
+			UserAgent:      session.UserAgent,
+			IPAddress:      session.IPAddress,
+			CreatedAt:      session.CreatedAt,
+			LastUsedAt:     session.LastUsedAt,
+			ExpiresAt:      session.ExpiresAt,
+			Current:        session.FamilyID == currentSessionID,
+			ImpersonatorID: session.ImpersonatorID,
+		})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Daftar sesi berhasil diambil", responses)
+}
+
+// RevokeSession mencabut satu sesi login milik user
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "ID sesi tidak valid", err.Error())
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.GetUint("user_id"), uint(sessionID)); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			utils.NotFoundResponse(c, "Sesi tidak ditemukan")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Gagal mencabut sesi", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sesi berhasil dicabut", nil)
+}
+
+// LogoutAll mencabut semua token dan sesi user di semua perangkat, termasuk access token yang masih berlaku.
+// Dipakai oleh POST /auth/logout-all dan DELETE /auth/sessions.
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if err := h.authService.RevokeAllSessions(userID); err != nil {
+		utils.InternalServerErrorResponse(c, "Logout dari semua perangkat gagal", err.Error())
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionLogout, auditEntityUser, userID, nil, nil)
+	event := newSecurityEvent(c, models.SecurityEventLogout, true, 0, "")
+	event.Detail = "all devices"
+	h.securityEventService.Record(event)
+
+	utils.ClearRefreshTokenCookie(c)
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout dari semua perangkat berhasil", nil)
+}
+
+// clientInfo mengambil IP dan user agent dari request
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler handles news categories
+type CategoryHandler struct {
+	categoryService service.CategoryService
+	auditService    service.AuditService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService service.CategoryService, auditService service.AuditService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		auditService:    auditService,
+	}
+}
+
+// ListPublic lists categories with the number of news that is live now
+func (h *CategoryHandler) ListPublic(c *gin.Context) {
+	h.list(c, true)
+}
+
+// List lists categories with the number of news of any status
+func (h *CategoryHandler) List(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *CategoryHandler) list(c *gin.Context, publishedOnly bool) {
+	categories, err := h.categoryService.List(publishedOnly)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch categories", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories retrieved successfully", categories)
+}
+
+// Create creates a category
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var request models.TaxonomyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	category, err := h.categoryService.Create(&request)
+	if err != nil {
+		h.handleError(c, "Failed to create category", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityCategory, category.ID, nil, category)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Category created successfully", category)
+}
+
+// Update replaces name, slug, description and order of a category
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	var request models.TaxonomyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, err := h.categoryService.Get(uint(id))
+	if err != nil {
+		h.handleError(c, "Failed to update category", err)
+		return
+	}
+
+	category, err := h.categoryService.Update(uint(id), &request)
+	if err != nil {
+		h.handleError(c, "Failed to update category", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityCategory, category.ID, before, category)
+
+	utils.SuccessResponse(c, http.StatusOK, "Category updated successfully", category)
+}
+
+// Delete deletes a category. News still in it moves to the category in ?move_to=.
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	var moveTo *uint
+	if value := c.Query("move_to"); value != "" {
+		target, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid move_to", err.Error())
+			return
+		}
+		targetID := uint(target)
+		moveTo = &targetID
+	}
+
+	before, err := h.categoryService.Get(uint(id))
+	if err != nil {
+		h.handleError(c, "Failed to delete category", err)
+		return
+	}
+
+	if err := h.categoryService.Delete(uint(id), moveTo); err != nil {
+		h.handleError(c, "Failed to delete category", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityCategory, before.ID, before, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
+}
+
+func (h *CategoryHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		utils.NotFoundResponse(c, "Category not found")
+	case errors.Is(err, service.ErrCategoryExists), errors.Is(err, service.ErrCategoryInUse):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler lets super admins act as another admin for a limited time
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationService
+	auditService         service.AuditService
+	securityEventService service.SecurityEventService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService service.ImpersonationService, auditService service.AuditService, securityEventService service.SecurityEventService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		auditService:         auditService,
+		securityEventService: securityEventService,
+	}
+}
+
+// Start issues an access token for the chosen admin. The token carries the super admin
+// as impersonator, so every request made with it is attributed to both users.
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	var request models.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	result, err := h.impersonationService.Start(c.GetUint("user_id"), &request, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			utils.NotFoundResponse(c, err.Error())
+		case errors.Is(err, service.ErrImpersonationNotAllowed), errors.Is(err, service.ErrCannotImpersonate):
+			utils.ForbiddenResponse(c, err.Error())
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to start impersonation", err.Error())
+		}
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionImpersonate, auditEntityUser, result.User.ID, nil, gin.H{
+		"reason":            request.Reason,
+		"session_id":        result.SessionID,
+		"expires_at":        result.ExpiresAt,
+		"allow_destructive": result.AllowDestructive,
+	})
+
+	// Muncul di riwayat keamanan admin yang dipakai akunnya
+	event := newSecurityEvent(c, models.SecurityEventImpersonation, true, result.User.ID, result.User.Username)
+	event.Detail = fmt.Sprintf("started by %s: %s", c.GetString("username"), request.Reason)
+	h.securityEventService.Record(event)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Impersonation started, send the access token as Bearer token until it expires", result)
+}
+
+// Stop ends the impersonation. Called with the impersonation token it ends that session,
+// called with the super admin's own token it ends every impersonation they started.
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	impersonatorID := c.GetUint("impersonator_id")
+	if impersonatorID == 0 {
+		h.stopAll(c)
+		return
+	}
+
+	session, err := h.impersonationService.StopSession(impersonatorID, c.GetString("session_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrNotImpersonating) {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to stop impersonation", err.Error())
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionImpersonateEnd, auditEntitySession, session.ID, nil, nil)
+
+	event := newSecurityEvent(c, models.SecurityEventImpersonation, true, 0, "")
+	event.Detail = "ended by " + c.GetString("impersonator_username")
+	h.securityEventService.Record(event)
+
+	utils.SuccessResponse(c, http.StatusOK, "Impersonation stopped", nil)
+}
+
+func (h *ImpersonationHandler) stopAll(c *gin.Context) {
+	if models.UserRole(c.GetString("role")) != models.SuperAdmin {
+		utils.ForbiddenResponse(c, service.ErrImpersonationNotAllowed.Error())
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	count, err := h.impersonationService.StopAll(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to stop impersonation", err.Error())
+		return
+	}
+
+	if count > 0 {
+		recordAudit(c, h.auditService, models.AuditActionImpersonateEnd, auditEntityUser, userID, nil, gin.H{"sessions": count})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Impersonation stopped", gin.H{"sessions_ended": count})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationHandler handles admin invitations
+type InvitationHandler struct {
+	invitationService service.InvitationService
+	auditService      service.AuditService
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(invitationService service.InvitationService, auditService service.AuditService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		auditService:      auditService,
+	}
+}
+
+// ListInvitations lists invitations that were not accepted or revoked yet
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.invitationService.List()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch invitations", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// CreateInvitation emails an invite link for the given role
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var request models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	invitation, err := h.invitationService.Invite(c.GetUint("user_id"), &request)
+	if err != nil {
+		h.handleError(c, "Failed to create invitation", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityInvitation, invitation.ID, nil, invitation)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invitation sent successfully", invitation)
+}
+
+// ResendInvitation sends a new link with a fresh expiry, earlier links stop working
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.invitationService.Resend(c.GetUint("user_id"), id)
+	if err != nil {
+		h.handleError(c, "Failed to resend invitation", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityInvitation, invitation.ID, nil, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation resent successfully", invitation)
+}
+
+// RevokeInvitation invalidates a pending invitation
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.invitationService.Revoke(c.GetUint("user_id"), id)
+	if err != nil {
+		h.handleError(c, "Failed to revoke invitation", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityInvitation, invitation.ID, nil, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation revoked successfully", invitation)
+}
+
+// AcceptInvitation membuat akun admin dari undangan, password dipilih sendiri oleh yang diundang
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var request models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestResponse(c, "Data request tidak valid", err.Error())
+		return
+	}
+
+	user, err := h.invitationService.Accept(&request)
+	if err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidInvitation) {
+			utils.BadRequestResponse(c, "Undangan tidak valid", err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, "Gagal menerima undangan", err.Error())
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityUser, user.ID, nil, user.ToResponse())
+
+	utils.SuccessResponse(c, http.StatusCreated, "Akun berhasil dibuat, silakan login", user.ToResponse())
+}
+
+func (h *InvitationHandler) parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid invitation ID", err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *InvitationHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvitationNotFound):
+		utils.NotFoundResponse(c, "Invitation not found")
+	case errors.Is(err, service.ErrRoleNotFound):
+		utils.BadRequestResponse(c, message, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		utils.ForbiddenResponse(c, "You cannot invite users to, or revoke invitations for, a role with permissions you do not have")
+	case errors.Is(err, service.ErrInvitationExists), errors.Is(err, service.ErrInvitationClosed):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPAccessHandler lets super admins change the IP and country allowlists without a restart
+type IPAccessHandler struct {
+	ipAccessService service.IPAccessService
+	auditService    service.AuditService
+}
+
+// NewIPAccessHandler creates a new IP access handler
+func NewIPAccessHandler(ipAccessService service.IPAccessService, auditService service.AuditService) *IPAccessHandler {
+	return &IPAccessHandler{
+		ipAccessService: ipAccessService,
+		auditService:    auditService,
+	}
+}
+
+// GetRules returns the allowlists currently enforced on the admin route groups
+func (h *IPAccessHandler) GetRules(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "IP access rules retrieved successfully", h.ipAccessService.GetRules())
+}
+
+// UpdateRules replaces the allowlists. Rules that would block the caller are rejected.
+func (h *IPAccessHandler) UpdateRules(c *gin.Context) {
+	var request models.IPAccessRulesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before := h.ipAccessService.GetRules()
+	rules := &models.IPAccessRules{Admin: *request.Admin, SuperAdmin: *request.SuperAdmin}
+
+	if err := h.ipAccessService.UpdateRules(rules, h.accessOrigin(c)); err != nil {
+		h.handleError(c, "Failed to update IP access rules", err)
+		return
+	}
+
+	h.recordChange(c, before, *rules)
+
+	utils.SuccessResponse(c, http.StatusOK, "IP access rules updated successfully", rules)
+}
+
+// ResetRules drops the stored allowlists so the rules from the environment apply again
+func (h *IPAccessHandler) ResetRules(c *gin.Context) {
+	before := h.ipAccessService.GetRules()
+
+	rules, err := h.ipAccessService.ResetRules(h.accessOrigin(c))
+	if err != nil {
+		h.handleError(c, "Failed to reset IP access rules", err)
+		return
+	}
+
+	h.recordChange(c, before, *rules)
+
+	utils.SuccessResponse(c, http.StatusOK, "IP access rules reset to the configured defaults", rules)
+}
+
+func (h *IPAccessHandler) accessOrigin(c *gin.Context) service.AccessOrigin {
+	origin := service.AccessOrigin{
+		ClientIP: c.ClientIP(),
+		RemoteIP: c.RemoteIP(),
+	}
+	if header := h.ipAccessService.CountryHeader(); header != "" {
+		origin.Country = c.GetHeader(header)
+	}
+	return origin
+}
+
+func (h *IPAccessHandler) recordChange(c *gin.Context, before, after models.IPAccessRules) {
+	entry := newAuditEntry(c, models.AuditActionUpdate, auditEntitySetting, service.SettingIPAccessRules)
+	h.auditService.Record(entry, before, after)
+}
+
+func (h *IPAccessHandler) handleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, service.ErrIPAccessLockout) {
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+		return
+	}
+	utils.BadRequestResponse(c, message, err.Error())
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys used to verify access tokens
+type JWKSHandler struct {
+	keyRing *utils.KeyRing
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(keyRing *utils.KeyRing) *JWKSHandler {
+	return &JWKSHandler{
+		keyRing: keyRing,
+	}
+}
+
+// GetKeys serves the key set as a plain JWKS document (RFC 7517), not wrapped in the API response
+// envelope, so standard JWT libraries can consume it directly
+func (h *JWKSHandler) GetKeys(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keyRing.JWKS())
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutHandler lets super admins inspect and clear login lockouts
+type LockoutHandler struct {
+	throttler service.LoginThrottler
+}
+
+// NewLockoutHandler creates a new lockout handler
+func NewLockoutHandler(throttler service.LoginThrottler) *LockoutHandler {
+	return &LockoutHandler{
+		throttler: throttler,
+	}
+}
+
+// ListLockouts returns every username and IP that is currently locked out
+func (h *LockoutHandler) ListLockouts(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Lockouts retrieved successfully", h.throttler.ListLockouts())
+}
+
+// ClearLockout removes the lockout and failed attempt counter of a username or IP
+func (h *LockoutHandler) ClearLockout(c *gin.Context) {
+	scope := c.Param("scope")
+	value := c.Param("value")
+
+	if err := h.throttler.ClearLockout(scope, value); err != nil {
+		if errors.Is(err, service.ErrLockoutNotFound) {
+			utils.NotFoundResponse(c, "Lockout not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to clear lockout", err.Error())
+		return
+	}
+
+	utils.NewLogger(c).Info(fmt.Sprintf("Login lockout for %s '%s' cleared by user %d", scope, value, c.GetUint("user_id")))
+
+	utils.SuccessResponse(c, http.StatusOK, "Lockout cleared successfully", nil)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/service"
 	"haslaw-be-services/internal/utils"
 	"net/http"
@@ -15,12 +16,14 @@ import (
 // MemberHandler handles member requests
 type MemberHandler struct {
 	memberService service.MemberService
+	auditService  service.AuditService
 }
 
 // NewMemberHandler creates a new member handler
-func NewMemberHandler(memberService service.MemberService) *MemberHandler {
+func NewMemberHandler(memberService service.MemberService, auditService service.AuditService) *MemberHandler {
 	return &MemberHandler{
 		memberService: memberService,
+		auditService:  auditService,
 	}
 }
 
@@ -129,6 +132,8 @@ func (h *MemberHandler) Create(c *gin.Context) {
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityMember, member.ID, nil, member)
+
 	utils.SuccessResponse(c, http.StatusCreated, "Member created successfully", member)
 }
 
@@ -140,6 +145,12 @@ func (h *MemberHandler) Update(c *gin.Context) {
 		return
 	}
 
+	before, err := h.memberService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "Member not found")
+		return
+	}
+
 	var req service.UpdateMemberRequest
 
 	// Check content type - support both JSON and form-data
@@ -209,6 +220,8 @@ func (h *MemberHandler) Update(c *gin.Context) {
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityMember, member.ID, before, member)
+
 	utils.SuccessResponse(c, http.StatusOK, "Member updated successfully", member)
 }
 
@@ -220,10 +233,18 @@ func (h *MemberHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	before, err := h.memberService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "Member not found")
+		return
+	}
+
 	if err := h.memberService.Delete(uint(id)); err != nil {
 		utils.BadRequestResponse(c, "Failed to delete member", err.Error())
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityMember, before.ID, before, nil)
+
 	utils.SuccessResponse(c, http.StatusOK, "Member deleted successfully", nil)
 }
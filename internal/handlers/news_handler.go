@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/service"
@@ -15,13 +16,15 @@ import (
 
 // NewsHandler handles news requests
 type NewsHandler struct {
-	newsService service.NewsService
+	newsService  service.NewsService
+	auditService service.AuditService
 }
 
 // NewNewsHandler creates a new news handler
-func NewNewsHandler(newsService service.NewsService) *NewsHandler {
+func NewNewsHandler(newsService service.NewsService, auditService service.AuditService) *NewsHandler {
 	return &NewsHandler{
-		newsService: newsService,
+		newsService:  newsService,
+		auditService: auditService,
 	}
 }
 
@@ -30,9 +33,12 @@ func (h *NewsHandler) GetAllPublicNews(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	orderBy := c.DefaultQuery("order_by", "created_at_desc")
-	category := c.Query("category")
+	filter := &models.NewsFilter{
+		Category: c.Query("category"), // Slug atau nama kategori
+		Tag:      c.Query("tag"),      // Slug atau nama tag
+	}
 
-	news, meta, err := h.newsService.GetPublished(page, limit, orderBy, category)
+	news, meta, err := h.newsService.GetPublished(page, limit, orderBy, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to fetch news", err.Error())
 		return
@@ -46,9 +52,18 @@ func (h *NewsHandler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	orderBy := c.DefaultQuery("order_by", "created_at_desc")
-	category := c.Query("category")
+	filter := &models.NewsFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+		Status:   models.NewsStatus(c.Query("status")), // Mis. InReview untuk antrian review
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		utils.BadRequestResponse(c, "Invalid status", fmt.Sprintf("status must be one of %v", models.ValidNewsStatuses))
+		return
+	}
 
-	news, meta, err := h.newsService.GetAll(page, limit, orderBy, category)
+	news, meta, err := h.newsService.GetAll(page, limit, orderBy, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to fetch news", err.Error())
 		return
@@ -57,6 +72,71 @@ func (h *NewsHandler) GetAll(c *gin.Context) {
 	utils.SuccessWithPagination(c, "News retrieved successfully", news, *meta)
 }
 
+// SearchPublicNews searches live news by relevance. Filters: category, tag, from and to
+// (YYYY-MM-DD or RFC 3339, on the publish date).
+func (h *NewsHandler) SearchPublicNews(c *gin.Context) {
+	filter, ok := newsSearchFilter(c)
+	if !ok {
+		return
+	}
+
+	h.search(c, filter, h.newsService.SearchPublished)
+}
+
+// Search searches news of every status for admin, including drafts. Same filters as
+// SearchPublicNews plus status.
+func (h *NewsHandler) Search(c *gin.Context) {
+	filter, ok := newsSearchFilter(c)
+	if !ok {
+		return
+	}
+
+	filter.Status = models.NewsStatus(c.Query("status"))
+	if filter.Status != "" && !filter.Status.IsValid() {
+		utils.BadRequestResponse(c, "Invalid status", fmt.Sprintf("status must be one of %v", models.ValidNewsStatuses))
+		return
+	}
+
+	h.search(c, filter, h.newsService.Search)
+}
+
+func (h *NewsHandler) search(c *gin.Context, filter *models.NewsFilter, search func(string, int, int, *models.NewsFilter) ([]models.NewsSearchResult, *utils.PaginationMeta, error)) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	results, meta, err := search(c.Query("q"), page, limit, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrSearchQuery) {
+			utils.BadRequestResponse(c, "Invalid search query", err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to search news", err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, "News retrieved successfully", results, *meta)
+}
+
+// newsSearchFilter reads the filters shared by both searches
+func newsSearchFilter(c *gin.Context) (*models.NewsFilter, bool) {
+	filter := &models.NewsFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		utils.BadRequestResponse(c, "Invalid from date", err.Error())
+		return nil, false
+	}
+	if filter.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		utils.BadRequestResponse(c, "Invalid to date", err.Error())
+		return nil, false
+	}
+
+	return filter, true
+}
+
 // GetByID gets news by ID
 func (h *NewsHandler) GetByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -74,6 +154,34 @@ func (h *NewsHandler) GetByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "News retrieved successfully", news)
 }
 
+// GetPublishedByID gets news by ID for the public, only while it is live
+func (h *NewsHandler) GetPublishedByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	news, err := h.newsService.GetPublishedByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "News retrieved successfully", news)
+}
+
+// GetPublishedBySlug gets news by slug for the public, only while it is live
+func (h *NewsHandler) GetPublishedBySlug(c *gin.Context) {
+	news, err := h.newsService.GetPublishedBySlug(c.Param("slug"))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "News retrieved successfully", news)
+}
+
 // GetBySlug gets news by slug
 func (h *NewsHandler) GetBySlug(c *gin.Context) {
 	slug := c.Param("slug")
@@ -106,6 +214,8 @@ func (h *NewsHandler) Create(c *gin.Context) {
 		req.Category = c.PostForm("category")
 		req.Status = models.NewsStatus(c.PostForm("status"))
 		req.Content = c.PostForm("content")
+		req.LegalAdvice = c.PostForm("legal_advice") == "true"
+		req.Tags = c.PostFormArray("tags")
 
 		// Handle file upload
 		file, err := c.FormFile("image")
@@ -133,12 +243,18 @@ func (h *NewsHandler) Create(c *gin.Context) {
 		return
 	}
 
-	news, err := h.newsService.Create(&req)
+	news, err := h.newsService.Create(&req, newsActor(c))
 	if err != nil {
-		utils.BadRequestResponse(c, "Failed to create news", err.Error())
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You are not allowed to create news with this status")
+			return
+		}
+		newsError(c, "Failed to create news", err)
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityNews, news.ID, nil, news)
+
 	utils.SuccessResponse(c, http.StatusCreated, "News created successfully", news)
 }
 
@@ -150,6 +266,12 @@ func (h *NewsHandler) Update(c *gin.Context) {
 		return
 	}
 
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
 	var req service.UpdateNewsRequest
 
 	// Check content type - support both JSON and form-data
@@ -167,6 +289,13 @@ func (h *NewsHandler) Update(c *gin.Context) {
 		req.Category = c.PostForm("category")
 		req.Status = models.NewsStatus(c.PostForm("status"))
 		req.Content = c.PostForm("content")
+		if legalAdvice, ok := c.GetPostForm("legal_advice"); ok {
+			value := legalAdvice == "true"
+			req.LegalAdvice = &value
+		}
+		if tags, ok := c.GetPostFormArray("tags"); ok {
+			req.Tags = tags
+		}
 
 		// Handle file upload (optional for update)
 		if file, err := c.FormFile("image"); err == nil {
@@ -183,12 +312,18 @@ func (h *NewsHandler) Update(c *gin.Context) {
 		return
 	}
 
-	news, err := h.newsService.Update(uint(id), &req)
+	news, err := h.newsService.Update(uint(id), &req, newsActor(c))
 	if err != nil {
-		utils.BadRequestResponse(c, "Failed to update news", err.Error())
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You can only edit your own unpublished news")
+			return
+		}
+		newsError(c, "Failed to update news", err)
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityNews, news.ID, before, news)
+
 	utils.SuccessResponse(c, http.StatusOK, "News updated successfully", news)
 }
 
@@ -200,11 +335,19 @@ func (h *NewsHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
 	if err := h.newsService.Delete(uint(id)); err != nil {
 		utils.BadRequestResponse(c, "Failed to delete news", err.Error())
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityNews, before.ID, before, nil)
+
 	utils.SuccessResponse(c, http.StatusOK, "News deleted successfully", nil)
 }
 
@@ -228,7 +371,7 @@ func (h *NewsHandler) GetDraftByID(c *gin.Context) {
 	h.GetByID(c) // Same logic as GetByID
 }
 
-// PublishDraft publishes a draft news
+// PublishDraft publishes approved or scheduled news, as allowed by the workflow
 func (h *NewsHandler) PublishDraft(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -236,11 +379,278 @@ func (h *NewsHandler) PublishDraft(c *gin.Context) {
 		return
 	}
 
-	news, err := h.newsService.Publish(uint(id))
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	news, err := h.newsService.Publish(uint(id), newsActor(c))
 	if err != nil {
-		utils.BadRequestResponse(c, "Failed to publish news", err.Error())
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You are not allowed to publish news")
+			return
+		}
+		newsError(c, "Failed to publish news", err)
 		return
 	}
 
+	recordAudit(c, h.auditService, models.AuditActionPublish, auditEntityNews, news.ID, before, news)
+
 	utils.SuccessResponse(c, http.StatusOK, "News published successfully", news)
 }
+
+// Schedule publishes a draft at publish_at, or sets when live news expires (unpublish_at)
+func (h *NewsHandler) Schedule(c *gin.Context) {
+	h.changeSchedule(c, "News scheduled successfully", func(id uint, request *models.ScheduleNewsRequest) (*models.News, error) {
+		return h.newsService.Schedule(id, request, newsActor(c))
+	})
+}
+
+// Reschedule moves publish_at and/or unpublish_at of scheduled news
+func (h *NewsHandler) Reschedule(c *gin.Context) {
+	h.changeSchedule(c, "News rescheduled successfully", func(id uint, request *models.ScheduleNewsRequest) (*models.News, error) {
+		return h.newsService.Reschedule(id, request, newsActor(c))
+	})
+}
+
+// CancelSchedule turns scheduled news back into a draft, or removes the expiry of live news
+func (h *NewsHandler) CancelSchedule(c *gin.Context) {
+	h.changeSchedule(c, "News schedule cancelled", func(id uint, _ *models.ScheduleNewsRequest) (*models.News, error) {
+		return h.newsService.CancelSchedule(id, newsActor(c))
+	})
+}
+
+func (h *NewsHandler) changeSchedule(c *gin.Context, message string, apply func(uint, *models.ScheduleNewsRequest) (*models.News, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	var request models.ScheduleNewsRequest
+	if c.Request.Method != http.MethodDelete {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	news, err := apply(uint(id), &request)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You are not allowed to schedule news")
+			return
+		}
+		newsError(c, "Failed to schedule news", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityNews, news.ID, before, news)
+
+	utils.SuccessResponse(c, http.StatusOK, message, news)
+}
+
+// GetRevisions lists the saved revisions of a news item without content, newest first
+func (h *NewsHandler) GetRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	revisions, meta, err := h.newsService.ListRevisions(uint(id), page, limit)
+	if err != nil {
+		newsLookupError(c, "Failed to fetch news revisions", err)
+		return
+	}
+
+	utils.SuccessWithPagination(c, "News revisions retrieved successfully", revisions, *meta)
+}
+
+// GetRevision gets one revision including its content
+func (h *NewsHandler) GetRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid revision number", err.Error())
+		return
+	}
+
+	revision, err := h.newsService.GetRevision(uint(id), number)
+	if err != nil {
+		newsLookupError(c, "Failed to fetch news revision", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "News revision retrieved successfully", revision)
+}
+
+// DiffRevisions compares the revisions given by the from and to query parameters
+func (h *NewsHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid from revision", "from and to must be revision numbers")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid to revision", "from and to must be revision numbers")
+		return
+	}
+
+	diff, err := h.newsService.DiffRevisions(uint(id), from, to)
+	if err != nil {
+		newsLookupError(c, "Failed to compare news revisions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "News revisions compared successfully", diff)
+}
+
+// RestoreRevision puts the title, category, content and image of an old revision back
+// on the news, saved as a new revision
+func (h *NewsHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid revision number", err.Error())
+		return
+	}
+
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	news, err := h.newsService.RestoreRevision(uint(id), number, newsActor(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You can only edit your own unpublished news")
+			return
+		}
+		newsError(c, "Failed to restore news revision", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityNews, news.ID, before, news)
+
+	utils.SuccessResponse(c, http.StatusOK, fmt.Sprintf("News restored from revision %d", number), news)
+}
+
+// Transition moves the news along the editorial workflow, e.g. submit for review,
+// approve or request changes with a comment
+func (h *NewsHandler) Transition(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	var request models.NewsTransitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, err := h.newsService.GetByID(uint(id))
+	if err != nil {
+		utils.NotFoundResponse(c, "News not found")
+		return
+	}
+
+	news, err := h.newsService.Transition(uint(id), &request, newsActor(c))
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			utils.ForbiddenResponse(c, "You are not allowed to make this status change")
+			return
+		}
+		newsError(c, "Failed to change news status", err)
+		return
+	}
+
+	action := models.AuditActionUpdate
+	if news.Status == models.Posted {
+		action = models.AuditActionPublish
+	}
+	recordAudit(c, h.auditService, action, auditEntityNews, news.ID, before, news)
+
+	utils.SuccessResponse(c, http.StatusOK, fmt.Sprintf("News moved to %s", news.Status), news)
+}
+
+// GetTransitions lists the status history of a news item with reviewer comments, oldest first
+func (h *NewsHandler) GetTransitions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid news ID", err.Error())
+		return
+	}
+
+	transitions, err := h.newsService.ListTransitions(uint(id))
+	if err != nil {
+		newsLookupError(c, "Failed to fetch news status history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "News status history retrieved successfully", transitions)
+}
+
+// newsError maps workflow and scheduling errors of a change to a response. Callers
+// handle service.ErrForbidden themselves to give a specific message.
+func newsError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrNewsNotFound), errors.Is(err, service.ErrRevisionNotFound):
+		utils.NotFoundResponse(c, err.Error())
+	case errors.Is(err, service.ErrLegalApprovalRequired):
+		utils.ForbiddenResponse(c, err.Error())
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrNewsLocked), errors.Is(err, service.ErrAlreadyScheduled):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
+
+func newsLookupError(c *gin.Context, message string, err error) {
+	if errors.Is(err, service.ErrNewsNotFound) || errors.Is(err, service.ErrRevisionNotFound) {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+	utils.InternalServerErrorResponse(c, message, err.Error())
+}
+
+// newsActor builds the actor for ownership and publish checks from the permissions loaded by RequirePermission
+func newsActor(c *gin.Context) service.NewsActor {
+	permissions, _ := c.Get("permissions")
+	granted, _ := permissions.([]models.Permission)
+	return service.NewsActor{
+		UserID:      c.GetUint("user_id"),
+		Username:    c.GetString("username"),
+		Permissions: granted,
+	}
+}
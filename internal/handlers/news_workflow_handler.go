@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewsWorkflowHandler exposes the editorial workflow and lets super admins change it
+type NewsWorkflowHandler struct {
+	workflowService service.NewsWorkflowService
+	auditService    service.AuditService
+}
+
+// NewNewsWorkflowHandler creates a new news workflow handler
+func NewNewsWorkflowHandler(workflowService service.NewsWorkflowService, auditService service.AuditService) *NewsWorkflowHandler {
+	return &NewsWorkflowHandler{
+		workflowService: workflowService,
+		auditService:    auditService,
+	}
+}
+
+// GetWorkflow returns the allowed status changes with their permissions
+func (h *NewsWorkflowHandler) GetWorkflow(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "News workflow retrieved successfully", h.workflowService.GetWorkflow())
+}
+
+// UpdateWorkflow replaces the list of allowed status changes
+func (h *NewsWorkflowHandler) UpdateWorkflow(c *gin.Context) {
+	var request models.NewsWorkflow
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before := h.workflowService.GetWorkflow()
+
+	if err := h.workflowService.UpdateWorkflow(&request); err != nil {
+		utils.BadRequestResponse(c, "Failed to update news workflow", err.Error())
+		return
+	}
+
+	h.recordChange(c, before, request)
+
+	utils.SuccessResponse(c, http.StatusOK, "News workflow updated successfully", request)
+}
+
+// ResetWorkflow drops the stored workflow so the default one applies again
+func (h *NewsWorkflowHandler) ResetWorkflow(c *gin.Context) {
+	before := h.workflowService.GetWorkflow()
+
+	workflow, err := h.workflowService.ResetWorkflow()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to reset news workflow", err.Error())
+		return
+	}
+
+	h.recordChange(c, before, *workflow)
+
+	utils.SuccessResponse(c, http.StatusOK, "News workflow reset to the default", workflow)
+}
+
+func (h *NewsWorkflowHandler) recordChange(c *gin.Context, before, after models.NewsWorkflow) {
+	entry := newAuditEntry(c, models.AuditActionUpdate, auditEntitySetting, service.SettingNewsWorkflow)
+	h.auditService.Record(entry, before, after)
+}
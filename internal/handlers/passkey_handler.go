@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyHandler handles WebAuthn passkey enrollment and passkey login
+type PasskeyHandler struct {
+	passkeyService       service.PasskeyService
+	authService          service.AuthService
+	auditService         service.AuditService
+	securityEventService service.SecurityEventService
+}
+
+// NewPasskeyHandler creates a new passkey handler
+func NewPasskeyHandler(passkeyService service.PasskeyService, authService service.AuthService, auditService service.AuditService, securityEventService service.SecurityEventService) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService:       passkeyService,
+		authService:          authService,
+		auditService:         auditService,
+		securityEventService: securityEventService,
+	}
+}
+
+// BeginRegistration returns the options for navigator.credentials.create()
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	options, err := h.passkeyService.BeginRegistration(c.GetUint("user_id"))
+	if err != nil {
+		h.handleError(c, "Failed to start passkey registration", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Create the passkey in the browser, then send the credential together with the session token", options)
+}
+
+// FinishRegistration verifies the new credential and stores it
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	var request models.PasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(c.GetUint("user_id"), &request)
+	if err != nil {
+		h.handleError(c, "Failed to register passkey", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityPasskey, passkey.ID, nil, passkey)
+
+	response := gin.H{"passkey": passkey}
+	liftRestriction(c, h.authService, response)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Passkey registered successfully", response)
+}
+
+// List returns the passkeys of the current user
+func (h *PasskeyHandler) List(c *gin.Context) {
+	passkeys, err := h.passkeyService.List(c.GetUint("user_id"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch passkeys", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Passkeys retrieved successfully", passkeys)
+}
+
+// Delete removes one of the current user's passkeys
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid passkey ID", err.Error())
+		return
+	}
+
+	if err := h.passkeyService.Delete(c.GetUint("user_id"), uint(id)); err != nil {
+		h.handleError(c, "Failed to delete passkey", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityPasskey, uint(id), nil, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Passkey deleted successfully", nil)
+}
+
+// BeginLogin returns the options for navigator.credentials.get(). Send the
+// challenge_token from the password step to use the passkey as a second factor.
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	var request models.PasskeyLoginBeginRequest
+	if err := c.ShouldBindJSON(&request); err != nil && c.Request.ContentLength > 0 {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	options, err := h.passkeyService.BeginLogin(request.ChallengeToken)
+	if err != nil {
+		respondLoginError(c, "passkey login", err, "Login dengan passkey gagal")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Use a passkey in the browser, then send the credential together with the session token", options)
+}
+
+// FinishLogin verifies the assertion and signs the user in
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	var request models.PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	result, err := h.passkeyService.FinishLogin(&request, clientInfo(c))
+	if err != nil {
+		recordLoginAudit(c, h.auditService, models.AuditActionLoginFailed, 0, "")
+		recordLoginEvent(c, h.securityEventService, securityMethodPasskey, 0, "", err)
+		respondLoginError(c, "passkey login", err, "Login dengan passkey gagal")
+		return
+	}
+
+	recordLoginAudit(c, h.auditService, models.AuditActionLogin, result.User.ID, result.User.Username)
+	recordLoginEvent(c, h.securityEventService, securityMethodPasskey, result.User.ID, result.User.Username, nil)
+	respondWithLogin(c, result, "Login berhasil")
+}
+
+func (h *PasskeyHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrPasskeyNotFound):
+		utils.NotFoundResponse(c, err.Error())
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		utils.ForbiddenResponse(c, err.Error())
+	case errors.Is(err, service.ErrPasskeyAlreadyExists):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler handles role and permission management
+type RoleHandler struct {
+	roleService  service.RoleService
+	auditService service.AuditService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleService service.RoleService, auditService service.AuditService) *RoleHandler {
+	return &RoleHandler{
+		roleService:  roleService,
+		auditService: auditService,
+	}
+}
+
+// ListRoles lists every role with its permissions
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch roles", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Roles retrieved successfully", roles)
+}
+
+// ListPermissions lists every permission that can be assigned to a role
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Permissions retrieved successfully", models.ValidPermissions)
+}
+
+// GetRole gets a single role by name
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(models.UserRole(c.Param("name")))
+	if err != nil {
+		h.handleError(c, "Failed to fetch role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role retrieved successfully", role)
+}
+
+// CreateRole creates a custom role
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var request models.CreateRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	role, err := h.roleService.CreateRole(models.UserRole(c.GetString("role")), &request)
+	if err != nil {
+		h.handleError(c, "Failed to create role", err)
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, models.AuditActionCreate, auditEntityRole, string(role.Name)), nil, role)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Role created successfully", role)
+}
+
+// UpdateRole replaces the description and permissions of a role
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var request models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, err := h.roleService.GetRole(models.UserRole(c.Param("name")))
+	if err != nil {
+		h.handleError(c, "Failed to update role", err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(models.UserRole(c.GetString("role")), models.UserRole(c.Param("name")), &request)
+	if err != nil {
+		h.handleError(c, "Failed to update role", err)
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, models.AuditActionUpdate, auditEntityRole, string(role.Name)), before, role)
+
+	utils.SuccessResponse(c, http.StatusOK, "Role updated successfully", role)
+}
+
+// DeleteRole deletes a custom role that is no longer assigned to anyone
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	before, err := h.roleService.GetRole(models.UserRole(c.Param("name")))
+	if err != nil {
+		h.handleError(c, "Failed to delete role", err)
+		return
+	}
+
+	if err := h.roleService.DeleteRole(before.Name); err != nil {
+		h.handleError(c, "Failed to delete role", err)
+		return
+	}
+
+	h.auditService.Record(newAuditEntry(c, models.AuditActionDelete, auditEntityRole, string(before.Name)), before, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Role deleted successfully", nil)
+}
+
+func (h *RoleHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		utils.NotFoundResponse(c, "Role not found")
+	case errors.Is(err, service.ErrForbidden):
+		utils.ForbiddenResponse(c, "You cannot grant permissions you do not have")
+	case errors.Is(err, service.ErrRoleExists), errors.Is(err, service.ErrRoleInUse), errors.Is(err, service.ErrRoleImmutable):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
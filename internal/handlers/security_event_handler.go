@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Methods recorded on login and password change events
+const (
+	securityMethodPassword = "password"
+	securityMethodTOTP     = "totp"
+	securityMethodPasskey  = "passkey"
+	securityMethodSSO      = "sso"
+	securityMethodReset    = "reset"
+)
+
+// SecurityEventHandler exposes the login history and security event feed
+type SecurityEventHandler struct {
+	securityEventService service.SecurityEventService
+	userService          service.UserService
+}
+
+// NewSecurityEventHandler creates a new security event handler
+func NewSecurityEventHandler(securityEventService service.SecurityEventService, userService service.UserService) *SecurityEventHandler {
+	return &SecurityEventHandler{
+		securityEventService: securityEventService,
+		userService:          userService,
+	}
+}
+
+// GetMySecurityEvents lists the security events of the current user, newest first
+func (h *SecurityEventHandler) GetMySecurityEvents(c *gin.Context) {
+	h.listEvents(c, c.GetUint("user_id"))
+}
+
+// GetUserSecurityEvents lists the security events of another user. Like the other
+// account management endpoints, the actor must hold every permission of the user's role.
+func (h *SecurityEventHandler) GetUserSecurityEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	target, err := h.userService.GetAdmin(uint(id))
+	if err == nil {
+		err = h.userService.CheckOutranks(c.GetUint("user_id"), target)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			utils.NotFoundResponse(c, "User not found")
+		case errors.Is(err, service.ErrForbidden):
+			utils.ForbiddenResponse(c, "You cannot view the security history of an account with permissions you do not have")
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to fetch security events", err.Error())
+		}
+		return
+	}
+
+	h.listEvents(c, target.ID)
+}
+
+// listEvents supports the filters type, success (true/false), from and to
+// (YYYY-MM-DD or RFC 3339, a date-only "to" is inclusive)
+func (h *SecurityEventHandler) listEvents(c *gin.Context, userID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filter := models.SecurityEventFilter{
+		Type: models.SecurityEventType(c.Query("type")),
+	}
+
+	if filter.Type != "" && !filter.Type.IsValid() {
+		utils.BadRequestResponse(c, "Invalid type", fmt.Sprintf("type must be one of %v", models.ValidSecurityEventTypes))
+		return
+	}
+
+	if success := c.Query("success"); success != "" {
+		value, err := strconv.ParseBool(success)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid success filter", err.Error())
+			return
+		}
+		filter.Success = &value
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		utils.BadRequestResponse(c, "Invalid from date", err.Error())
+		return
+	}
+	if filter.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		utils.BadRequestResponse(c, "Invalid to date", err.Error())
+		return
+	}
+
+	events, meta, err := h.securityEventService.ListForUser(userID, &filter, page, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFoundResponse(c, "User not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch security events", err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(c, "Security events retrieved successfully", events, *meta)
+}
+
+// newSecurityEvent fills the request details of a security event. The user defaults
+// to the authenticated user; pass userID 0 and a username for a failed login.
+func newSecurityEvent(c *gin.Context, eventType models.SecurityEventType, success bool, userID uint, username string) *models.SecurityEvent {
+	if userID == 0 && username == "" {
+		userID = c.GetUint("user_id")
+		username = c.GetString("username")
+	}
+
+	event := &models.SecurityEvent{
+		Username:  username,
+		Type:      eventType,
+		Success:   success,
+		TraceID:   utils.GetTraceID(c),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+
+	if userID != 0 {
+		event.UserID = &userID
+	}
+
+	return event
+}
+
+// recordPasswordChangeFailure records a password change rejected because of a wrong
+// current password, which may mean someone else is using an open session
+func recordPasswordChangeFailure(c *gin.Context, securityEventService service.SecurityEventService, err error) {
+	if !errors.Is(err, service.ErrInvalidCurrentPassword) {
+		return
+	}
+
+	event := newSecurityEvent(c, models.SecurityEventPasswordChange, false, 0, "")
+	event.Method = securityMethodPassword
+	event.Detail = err.Error()
+	securityEventService.Record(event)
+}
+
+// recordLoginEvent records a login attempt. err is the reason of a failed attempt.
+func recordLoginEvent(c *gin.Context, securityEventService service.SecurityEventService, method string, userID uint, username string, err error) {
+	event := newSecurityEvent(c, models.SecurityEventLogin, err == nil, userID, username)
+	event.Method = method
+	if err != nil {
+		event.Detail = err.Error()
+	}
+	securityEventService.Record(event)
+}
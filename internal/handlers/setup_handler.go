@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHandler handles the one-time creation of the first super admin
+type SetupHandler struct {
+	bootstrapService service.BootstrapService
+	auditService     service.AuditService
+}
+
+// NewSetupHandler creates a new setup handler
+func NewSetupHandler(bootstrapService service.BootstrapService, auditService service.AuditService) *SetupHandler {
+	return &SetupHandler{
+		bootstrapService: bootstrapService,
+		auditService:     auditService,
+	}
+}
+
+// Setup creates the first super admin with the setup token printed at startup
+func (h *SetupHandler) Setup(c *gin.Context) {
+	var request models.SetupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	user, err := h.bootstrapService.Setup(&request)
+	if err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrSetupNotAvailable):
+			utils.NotFoundResponse(c, "Setup is not available")
+		case errors.Is(err, service.ErrInvalidSetupToken):
+			utils.UnauthorizedResponse(c, "Invalid setup token")
+		default:
+			utils.BadRequestResponse(c, "Failed to complete setup", err.Error())
+		}
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityUser, user.ID, nil, user)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Super admin created, you can now log in", user.ToResponse())
+}
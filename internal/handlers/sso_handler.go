@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ssoStateMaxAge is how long the browser has to return from the identity provider
+const ssoStateMaxAge = 10 * 60
+
+// SSOHandler handles sign in through the OpenID Connect provider. The callback
+// stores the refresh token in the usual cookie and sends the browser back to the
+// frontend, which then gets an access token from /auth/refresh.
+type SSOHandler struct {
+	ssoService           service.SSOService
+	auditService         service.AuditService
+	securityEventService service.SecurityEventService
+	postLoginURL         string
+}
+
+// NewSSOHandler creates a new SSO handler
+func NewSSOHandler(ssoService service.SSOService, auditService service.AuditService, securityEventService service.SecurityEventService, postLoginURL string) *SSOHandler {
+	return &SSOHandler{
+		ssoService:           ssoService,
+		auditService:         auditService,
+		securityEventService: securityEventService,
+		postLoginURL:         postLoginURL,
+	}
+}
+
+// Login mengarahkan browser ke halaman login identity provider
+func (h *SSOHandler) Login(c *gin.Context) {
+	login, err := h.ssoService.Begin()
+	if err != nil {
+		utils.NewLogger(c).AuthError("SSO login", err.Error())
+		utils.ErrorResponse(c, http.StatusBadGateway, "Identity provider tidak dapat dihubungi", err.Error())
+		return
+	}
+
+	utils.SetSSOStateCookie(c, login.StateToken, ssoStateMaxAge)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// Callback menyelesaikan login SSO lalu mengarahkan browser kembali ke frontend
+func (h *SSOHandler) Callback(c *gin.Context) {
+	stateToken, _ := utils.SSOStateFromCookie(c)
+	utils.ClearSSOStateCookie(c)
+
+	if providerError := c.Query("error"); providerError != "" {
+		utils.NewLogger(c).AuthError("SSO callback", providerError+": "+c.Query("error_description"))
+		h.redirect(c, url.Values{"error": {providerError}}, nil)
+		return
+	}
+
+	result, err := h.ssoService.Complete(c.Query("code"), c.Query("state"), stateToken, clientInfo(c))
+	if err != nil {
+		utils.NewLogger(c).AuthError("SSO callback", err.Error())
+		recordLoginAudit(c, h.auditService, models.AuditActionLoginFailed, 0, "")
+		recordLoginEvent(c, h.securityEventService, securityMethodSSO, 0, "", err)
+		h.redirect(c, url.Values{"error": {ssoErrorCode(err)}}, nil)
+		return
+	}
+
+	if result.TwoFactorRequired {
+		// Challenge token saja tidak cukup untuk login, kode TOTP atau passkey tetap dibutuhkan.
+		// Token dikirim lewat fragment supaya tidak tercatat di log server, proxy atau header Referer.
+		h.redirect(c, url.Values{
+			"two_factor_required": {"true"},
+			"methods":             {strings.Join(twoFactorMethods(result.User), ",")},
+		}, url.Values{"challenge_token": {result.ChallengeToken}})
+		return
+	}
+
+	recordLoginAudit(c, h.auditService, models.AuditActionLogin, result.User.ID, result.User.Username)
+	recordLoginEvent(c, h.securityEventService, securityMethodSSO, result.User.ID, result.User.Username, nil)
+	// Frontend mengambil CSRF token lewat GET /auth/csrf sebelum memanggil /auth/refresh
+	if _, ok := setRefreshTokenCookie(c, result.RefreshToken); !ok {
+		return
+	}
+
+	query := url.Values{"login": {"success"}}
+	if result.PasswordChangeRequired {
+		query.Set("password_change_required", "true")
+	}
+	if result.TwoFactorSetupRequired {
+		query.Set("two_factor_setup_required", "true")
+	}
+	h.redirect(c, query, nil)
+}
+
+// redirect sends the browser to the post login URL. Values in fragment never reach
+// a server, so they are used for anything secret.
+func (h *SSOHandler) redirect(c *gin.Context, query, fragment url.Values) {
+	target, err := url.Parse(h.postLoginURL)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "OIDC_POST_LOGIN_URL tidak valid", err.Error())
+		return
+	}
+
+	values := target.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	target.RawQuery = values.Encode()
+
+	if fragment == nil {
+		c.Redirect(http.StatusFound, target.String())
+		return
+	}
+
+	target.Fragment, target.RawFragment = "", ""
+	c.Redirect(http.StatusFound, target.String()+"#"+fragment.Encode())
+}
+
+// ssoErrorCode maps an SSO failure to a stable code the frontend can show a message for
+func ssoErrorCode(err error) string {
+	switch {
+	case errors.Is(err, service.ErrSSOInvalidState):
+		return "invalid_state"
+	case errors.Is(err, service.ErrSSOEmailNotVerified):
+		return "email_not_verified"
+	case errors.Is(err, service.ErrSSOAccountNotLinked):
+		return "account_not_linked"
+	default:
+		return "login_failed"
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler handles news tags
+type TagHandler struct {
+	tagService   service.TagService
+	auditService service.AuditService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService service.TagService, auditService service.AuditService) *TagHandler {
+	return &TagHandler{
+		tagService:   tagService,
+		auditService: auditService,
+	}
+}
+
+// ListPublic lists tags with the number of news that is live now
+func (h *TagHandler) ListPublic(c *gin.Context) {
+	h.list(c, true)
+}
+
+// List lists tags with the number of news of any status
+func (h *TagHandler) List(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *TagHandler) list(c *gin.Context, publishedOnly bool) {
+	tags, err := h.tagService.List(publishedOnly)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch tags", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tags retrieved successfully", tags)
+}
+
+// Create creates a tag
+func (h *TagHandler) Create(c *gin.Context) {
+	var request models.TaxonomyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	tag, err := h.tagService.Create(&request)
+	if err != nil {
+		h.handleError(c, "Failed to create tag", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionCreate, auditEntityTag, tag.ID, nil, tag)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// Update replaces name, slug, description and order of a tag
+func (h *TagHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid tag ID", err.Error())
+		return
+	}
+
+	var request models.TaxonomyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	before, err := h.tagService.Get(uint(id))
+	if err != nil {
+		h.handleError(c, "Failed to update tag", err)
+		return
+	}
+
+	tag, err := h.tagService.Update(uint(id), &request)
+	if err != nil {
+		h.handleError(c, "Failed to update tag", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionUpdate, auditEntityTag, tag.ID, before, tag)
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+// Delete deletes a tag and removes it from every news item
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid tag ID", err.Error())
+		return
+	}
+
+	before, err := h.tagService.Get(uint(id))
+	if err != nil {
+		h.handleError(c, "Failed to delete tag", err)
+		return
+	}
+
+	if err := h.tagService.Delete(uint(id)); err != nil {
+		h.handleError(c, "Failed to delete tag", err)
+		return
+	}
+
+	recordAudit(c, h.auditService, models.AuditActionDelete, auditEntityTag, before.ID, before, nil)
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag deleted successfully", nil)
+}
+
+func (h *TagHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		utils.NotFoundResponse(c, "Tag not found")
+	case errors.Is(err, service.ErrTagExists):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/service"
+	"haslaw-be-services/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler handles TOTP enrollment and the global 2FA policy
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+	authService      service.AuthService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService, authService service.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		authService:      authService,
+	}
+}
+
+// Setup starts TOTP enrollment and returns the secret and provisioning URI
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	setup, err := h.twoFactorService.Setup(c.GetUint("user_id"))
+	if err != nil {
+		h.handleError(c, "Failed to start two-factor setup", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the provisioning URI with your authenticator app, then confirm with a code", setup)
+}
+
+// Enable confirms enrollment with the first TOTP code and returns recovery codes
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var request models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	codes, err := h.twoFactorService.Enable(c.GetUint("user_id"), request.Code)
+	if err != nil {
+		h.handleError(c, "Failed to enable two-factor authentication", err)
+		return
+	}
+
+	response := gin.H{"recovery_codes": codes}
+	liftRestriction(c, h.authService, response)
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled. Store the recovery codes somewhere safe, they are shown only once", response)
+}
+
+// Disable turns off two-factor authentication for the current user
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var request models.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.GetUint("user_id"), request.Password, request.Code); err != nil {
+		h.handleError(c, "Failed to disable two-factor authentication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the current user
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var request models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.GetUint("user_id"), request.Code)
+	if err != nil {
+		h.handleError(c, "Failed to regenerate recovery codes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes regenerated", gin.H{"recovery_codes": codes})
+}
+
+// GetPolicy returns the global two-factor policy
+func (h *TwoFactorHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.twoFactorService.GetPolicy()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch two-factor policy", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor policy retrieved successfully", policy)
+}
+
+// UpdatePolicy changes whether super admins must use two-factor authentication
+func (h *TwoFactorHandler) UpdatePolicy(c *gin.Context) {
+	var request models.TwoFactorPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.twoFactorService.SetRequireForSuperAdmins(*request.RequireForSuperAdmins); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to update two-factor policy", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor policy updated successfully", service.TwoFactorPolicy{
+		RequireForSuperAdmins: *request.RequireForSuperAdmins,
+	})
+}
+
+// liftRestriction rotates the current session so the 2FA setup restriction is lifted
+// right away, and adds the new access token to the response
+func liftRestriction(c *gin.Context, authService service.AuthService, response gin.H) {
+	if c.GetString("restriction") == "" {
+		return
+	}
+
+	refreshToken, err := utils.RefreshTokenFromCookie(c)
+	if err != nil {
+		return
+	}
+
+	accessToken, newRefreshToken, err := authService.RefreshToken(refreshToken, clientInfo(c))
+	if err != nil {
+		return
+	}
+
+	csrfToken, err := utils.SetRefreshTokenCookie(c, newRefreshToken)
+	if err != nil {
+		return
+	}
+	response["access_token"] = accessToken
+	response["token_type"] = "Bearer"
+	response["expires_in"] = 900
+	response["csrf_token"] = csrfToken
+}
+
+func (h *TwoFactorHandler) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		utils.NotFoundResponse(c, "User not found")
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		utils.ForbiddenResponse(c, err.Error())
+	case errors.Is(err, service.ErrTwoFactorAlreadyActive), errors.Is(err, service.ErrTwoFactorNotActive):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	default:
+		utils.BadRequestResponse(c, message, err.Error())
+	}
+}
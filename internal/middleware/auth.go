@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/service"
 	"haslaw-be-services/internal/utils"
@@ -9,6 +10,52 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// restrictedRoutes lists the only routes a restricted token may call, keyed by restriction
+var restrictedRoutes = map[string]map[string]bool{
+	utils.RestrictionTwoFactorSetup: {
+		"POST /api/v1/auth/2fa/setup":                true,
+		"POST /api/v1/auth/2fa/enable":               true,
+		"POST /api/v1/auth/passkeys/register/begin":  true,
+		"POST /api/v1/auth/passkeys/register/finish": true,
+		"GET /api/v1/auth/profile":                   true,
+		"POST /api/v1/auth/logout":                   true,
+	},
+	utils.RestrictionPasswordChange: {
+		"POST /api/v1/auth/change-password": true,
+		"GET /api/v1/auth/profile":          true,
+		"POST /api/v1/auth/logout":          true,
+	},
+}
+
+// ImpersonatedByHeader marks responses to requests made with an impersonation token
+const ImpersonatedByHeader = "X-Impersonated-By"
+
+// impersonationBlockedRoutes change the subject's own credentials or sessions and are
+// never allowed while impersonating. Use /super-admin/impersonation/stop to end it.
+var impersonationBlockedRoutes = map[string]bool{
+	"POST /api/v1/auth/logout":                     true,
+	"POST /api/v1/auth/logout-all":                 true,
+	"PUT /api/v1/auth/profile":                     true,
+	"POST /api/v1/auth/change-password":            true,
+	"POST /api/v1/auth/2fa/setup":                  true,
+	"POST /api/v1/auth/2fa/enable":                 true,
+	"POST /api/v1/auth/2fa/disable":                true,
+	"POST /api/v1/auth/2fa/recovery-codes":         true,
+	"POST /api/v1/auth/passkeys/register/begin":    true,
+	"POST /api/v1/auth/passkeys/register/finish":   true,
+	"DELETE /api/v1/auth/passkeys/:id":             true,
+	"DELETE /api/v1/auth/sessions/:id":             true,
+	"DELETE /api/v1/auth/sessions":                 true,
+	"POST /api/v1/super-admin/impersonation/start": true,
+}
+
+// impersonationDestructiveRoutes are destructive without using DELETE. Like every
+// DELETE request they need an impersonation started with allow_destructive.
+var impersonationDestructiveRoutes = map[string]bool{
+	"POST /api/v1/super-admin/admins/:id/disable": true,
+	"PUT /api/v1/super-admin/admins/:id/role":     true,
+}
+
 func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -40,47 +87,119 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(token)
+		claims, err := authService.ValidateToken(token)
 		if err != nil {
 			utils.UnauthorizedResponse(c, "Invalid or expired token")
 			c.Abort()
 			return
 		}
 
+		if claims.Restriction != "" && !restrictedRoutes[claims.Restriction][c.Request.Method+" "+c.FullPath()] {
+			utils.ForbiddenResponse(c, restrictionMessage(claims.Restriction))
+			c.Abort()
+			return
+		}
+
+		if claims.IsImpersonation() {
+			c.Header(ImpersonatedByHeader, claims.ImpersonatorUsername)
+
+			if message := impersonationDenied(c, claims); message != "" {
+				utils.ForbiddenResponse(c, message)
+				c.Abort()
+				return
+			}
+
+			c.Set("impersonator_id", claims.ImpersonatorID)
+			c.Set("impersonator_username", claims.ImpersonatorUsername)
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("restriction", claims.Restriction)
+		c.Set("session_id", claims.SessionID)
 		c.Set("token", token)
 		c.Next()
 	}
 }
 
-func RequireRole(requiredRole models.UserRole) gin.HandlerFunc {
+// AuthOrAPIKeyMiddleware accepts either a JWT or an API key (X-API-Key header or
+// "Authorization: Bearer hsk_..."). API keys carry their scopes as the request
+// permissions, so they only pass RequirePermission checks, never RequireRole.
+func AuthOrAPIKeyMiddleware(authService service.AuthService, apiKeyService service.APIKeyService) gin.HandlerFunc {
+	jwtAuth := AuthMiddleware(authService)
+
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
-			utils.ForbiddenResponse(c, "User role not found")
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			if bearer := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); strings.HasPrefix(bearer, service.APIKeyPrefix) {
+				key = bearer
+			}
+		}
+
+		if key == "" {
+			jwtAuth(c)
+			return
+		}
+
+		apiKey, err := apiKeyService.Authenticate(key, c.ClientIP())
+		if err != nil {
+			utils.NewLogger(c).AuthError("api key", err.Error())
+			if errors.Is(err, service.ErrAPIKeyIPDenied) {
+				utils.ForbiddenResponse(c, err.Error())
+			} else {
+				utils.UnauthorizedResponse(c, "Invalid or expired API key")
+			}
 			c.Abort()
 			return
 		}
 
-		userRole := models.UserRole(role.(string))
+		c.Set("user_id", apiKey.CreatedByID)
+		c.Set("username", "api_key:"+apiKey.Name)
+		c.Set("api_key_id", apiKey.ID)
+		c.Set("permissions", apiKey.Scopes)
+		c.Next()
+	}
+}
 
-		// Check role hierarchy: SuperAdmin can access Admin routes, but not vice versa
-		hasPermission := false
+// impersonationDenied returns why the request is not allowed with the impersonation token,
+// or an empty string if it is
+func impersonationDenied(c *gin.Context, claims *utils.Claims) string {
+	route := c.Request.Method + " " + c.FullPath()
+	if impersonationBlockedRoutes[route] {
+		return "Not allowed while impersonating"
+	}
+
+	if (c.Request.Method == "DELETE" || impersonationDestructiveRoutes[route]) && !claims.AllowDestructive {
+		return "Destructive operations are not allowed while impersonating"
+	}
+
+	return ""
+}
 
-		switch requiredRole {
-		case models.SuperAdmin:
-			// Only SuperAdmin can access SuperAdmin routes
-			hasPermission = userRole == models.SuperAdmin
-		case models.Admin:
-			// Both Admin and SuperAdmin can access Admin routes
-			hasPermission = userRole == models.Admin || userRole == models.SuperAdmin
-		default:
-			hasPermission = userRole == requiredRole
+func restrictionMessage(restriction string) string {
+	switch restriction {
+	case utils.RestrictionTwoFactorSetup:
+		return "Two-factor authentication setup required before accessing this resource"
+	case utils.RestrictionPasswordChange:
+		return "Password change required before accessing this resource"
+	default:
+		return "Token is restricted"
+	}
+}
+
+// RequireRole only lets the given role through. Super admins pass every role check.
+// Prefer RequirePermission, which also works for custom roles.
+func RequireRole(requiredRole models.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := models.UserRole(c.GetString("role"))
+		if role == "" {
+			utils.ForbiddenResponse(c, "User role not found")
+			c.Abort()
+			return
 		}
 
-		if !hasPermission {
+		if role != requiredRole && role != models.SuperAdmin {
 			utils.ForbiddenResponse(c, "Insufficient permissions")
 			c.Abort()
 			return
@@ -94,12 +213,90 @@ func RequireSuperAdmin() gin.HandlerFunc {
 	return RequireRole(models.SuperAdmin)
 }
 
-func CORSMiddleware() gin.HandlerFunc {
+// RequirePermission requires every listed permission. The permissions of the
+// user's role are stored in the context as "permissions" for handlers.
+func RequirePermission(roleService service.RoleService, permissions ...models.Permission) gin.HandlerFunc {
+	return requirePermissions(roleService, permissions, true)
+}
+
+// RequireAnyPermission requires at least one of the listed permissions
+func RequireAnyPermission(roleService service.RoleService, permissions ...models.Permission) gin.HandlerFunc {
+	return requirePermissions(roleService, permissions, false)
+}
+
+func requirePermissions(roleService service.RoleService, required []models.Permission, all bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE")
+		granted, ok := loadPermissions(c, roleService)
+		if !ok {
+			return
+		}
+
+		allowed := all
+		for _, permission := range required {
+			has := models.HasPermission(granted, permission)
+			if all && !has {
+				allowed = false
+				break
+			}
+			if !all && has {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			utils.ForbiddenResponse(c, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// loadPermissions resolves the permissions of the current user once per request
+func loadPermissions(c *gin.Context, roleService service.RoleService) ([]models.Permission, bool) {
+	if permissions, exists := c.Get("permissions"); exists {
+		return permissions.([]models.Permission), true
+	}
+
+	role := c.GetString("role")
+	if role == "" {
+		utils.ForbiddenResponse(c, "User role not found")
+		c.Abort()
+		return nil, false
+	}
+
+	permissions, err := roleService.PermissionsFor(models.UserRole(role))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to load permissions", err.Error())
+		c.Abort()
+		return nil, false
+	}
+
+	c.Set("permissions", permissions)
+	return permissions, true
+}
+
+// CORSMiddleware only allows the configured origins. The matched origin is echoed back,
+// since browsers reject a wildcard origin on requests that carry cookies.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimRight(origin, "/")] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if origin != "" && allowed[origin] {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+			c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+			c.Header("Access-Control-Allow-Methods", "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE")
+			c.Header("Access-Control-Expose-Headers", ImpersonatedByHeader)
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -110,6 +307,26 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// CSRFMiddleware protects endpoints that act on the refresh token cookie with the
+// double-submit pattern: the X-CSRF-Token header must match the CSRF cookie.
+// Requests without a refresh token cookie carry no ambient credentials and pass.
+func CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := utils.RefreshTokenFromCookie(c); err != nil {
+			c.Next()
+			return
+		}
+
+		if !utils.ValidCSRFToken(c) {
+			utils.ForbiddenResponse(c, "Missing or invalid CSRF token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func SecurityHeadersMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("X-Frame-Options", "DENY")
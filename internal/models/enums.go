@@ -3,11 +3,16 @@ package models
 type NewsStatus string
 
 const (
-	Posted  NewsStatus = "Posted"
-	Drafted NewsStatus = "Drafted"
+	Posted           NewsStatus = "Posted"
+	Drafted          NewsStatus = "Drafted"
+	InReview         NewsStatus = "InReview"         // Menunggu review redaksi
+	ChangesRequested NewsStatus = "ChangesRequested" // Dikembalikan reviewer dengan komentar
+	Approved         NewsStatus = "Approved"         // Siap dipublish atau dijadwalkan
+	Scheduled        NewsStatus = "Scheduled"        // Tayang otomatis pada publish_at
+	Archived         NewsStatus = "Archived"         // Tidak tayang lagi, mis. setelah unpublish_at
 )
 
-var ValidNewsStatuses = []NewsStatus{Posted, Drafted}
+var ValidNewsStatuses = []NewsStatus{Posted, Drafted, InReview, ChangesRequested, Approved, Scheduled, Archived}
 
 func (ns NewsStatus) String() string {
 	return string(ns)
@@ -28,9 +33,15 @@ const (
 	SuperAdmin UserRole = "super_admin"
 
 	Admin UserRole = "admin"
+
+	Editor   UserRole = "editor"
+	Reviewer UserRole = "reviewer"
+	Author   UserRole = "author"
+	Partner  UserRole = "partner" // Menyetujui berita yang berisi nasihat hukum
 )
 
-var ValidUserRoles = []UserRole{SuperAdmin, Admin}
+// ValidUserRoles are the built-in roles. Super admins can add custom roles stored in the roles table.
+var ValidUserRoles = []UserRole{SuperAdmin, Admin, Editor, Reviewer, Author, Partner}
 
 func (ur UserRole) String() string {
 	return string(ur)
@@ -44,3 +55,159 @@ func (ur UserRole) IsValid() bool {
 	}
 	return false
 }
+
+type Permission string
+
+const (
+	PermissionAll Permission = "*"
+
+	PermissionNewsRead    Permission = "news:read"     // Lihat semua berita termasuk draft
+	PermissionNewsCreate  Permission = "news:create"   // Buat draft berita
+	PermissionNewsEditOwn Permission = "news:edit_own" // Edit berita milik sendiri
+	PermissionNewsEditAny Permission = "news:edit_any" // Edit berita siapa pun
+	PermissionNewsPublish Permission = "news:publish"  // Publish berita
+	PermissionNewsDelete  Permission = "news:delete"
+	PermissionNewsReview  Permission = "news:review"        // Setujui atau kembalikan berita yang di-review
+	PermissionNewsLegal   Permission = "news:approve_legal" // Setujui berita yang berisi nasihat hukum (partner)
+
+	PermissionNewsTaxonomy Permission = "news:taxonomy" // Kelola kategori dan tag
+
+	PermissionMembersRead   Permission = "members:read"
+	PermissionMembersWrite  Permission = "members:write"
+	PermissionMembersDelete Permission = "members:delete" // Hapus profil attorney
+
+	PermissionUsersManage    Permission = "users:manage"    // Kelola akun admin
+	PermissionRolesManage    Permission = "roles:manage"    // Kelola role dan permission
+	PermissionSettingsManage Permission = "settings:manage" // Kebijakan keamanan, lockout
+	PermissionAPIKeysManage  Permission = "api_keys:manage" // Kelola API key mesin
+	PermissionAuditRead      Permission = "audit:read"      // Lihat audit log
+)
+
+var ValidPermissions = []Permission{
+	PermissionAll,
+	PermissionNewsRead, PermissionNewsCreate, PermissionNewsEditOwn, PermissionNewsEditAny, PermissionNewsPublish, PermissionNewsDelete,
+	PermissionNewsReview, PermissionNewsLegal, PermissionNewsTaxonomy,
+	PermissionMembersRead, PermissionMembersWrite, PermissionMembersDelete,
+	PermissionUsersManage, PermissionRolesManage, PermissionSettingsManage, PermissionAPIKeysManage, PermissionAuditRead,
+}
+
+func (p Permission) String() string {
+	return string(p)
+}
+
+func (p Permission) IsValid() bool {
+	for _, permission := range ValidPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether granted contains required, either directly or through "*"
+func HasPermission(granted []Permission, required Permission) bool {
+	for _, permission := range granted {
+		if permission == PermissionAll || permission == required {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltInRolePermissions are the default permissions of the built-in roles
+var BuiltInRolePermissions = map[UserRole][]Permission{
+	SuperAdmin: {PermissionAll},
+	Admin: {
+		PermissionNewsRead, PermissionNewsCreate, PermissionNewsEditOwn, PermissionNewsEditAny, PermissionNewsPublish, PermissionNewsDelete,
+		PermissionNewsReview, PermissionNewsTaxonomy,
+		PermissionMembersRead, PermissionMembersWrite, PermissionMembersDelete,
+	},
+	Editor: {
+		PermissionNewsRead, PermissionNewsCreate, PermissionNewsEditOwn, PermissionNewsEditAny, PermissionNewsPublish, PermissionNewsDelete,
+		PermissionNewsReview, PermissionNewsTaxonomy,
+		PermissionMembersRead, PermissionMembersWrite,
+	},
+	Reviewer: {
+		PermissionNewsRead, PermissionNewsPublish, PermissionNewsReview,
+		PermissionMembersRead,
+	},
+	Author: {
+		PermissionNewsRead, PermissionNewsCreate, PermissionNewsEditOwn,
+		PermissionMembersRead,
+	},
+	Partner: {
+		PermissionNewsRead, PermissionNewsPublish, PermissionNewsReview, PermissionNewsLegal,
+		PermissionMembersRead,
+	},
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+	InvitationExpired  InvitationStatus = "expired"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate         AuditAction = "create"
+	AuditActionUpdate         AuditAction = "update"
+	AuditActionDelete         AuditAction = "delete"
+	AuditActionPublish        AuditAction = "publish"
+	AuditActionLogin          AuditAction = "login"
+	AuditActionLoginFailed    AuditAction = "login_failed"
+	AuditActionLogout         AuditAction = "logout"
+	AuditActionImpersonate    AuditAction = "impersonate"
+	AuditActionImpersonateEnd AuditAction = "impersonate_end"
+)
+
+var ValidAuditActions = []AuditAction{
+	AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionPublish,
+	AuditActionLogin, AuditActionLoginFailed, AuditActionLogout,
+	AuditActionImpersonate, AuditActionImpersonateEnd,
+}
+
+func (a AuditAction) IsValid() bool {
+	for _, action := range ValidAuditActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityEventType adalah jenis kejadian di riwayat keamanan user
+type SecurityEventType string
+
+const (
+	SecurityEventLogin          SecurityEventType = "login"
+	SecurityEventTokenRefresh   SecurityEventType = "token_refresh"
+	SecurityEventLogout         SecurityEventType = "logout"
+	SecurityEventPasswordChange SecurityEventType = "password_change"
+	SecurityEventImpersonation  SecurityEventType = "impersonation" // Super admin memakai akun ini
+)
+
+var ValidSecurityEventTypes = []SecurityEventType{
+	SecurityEventLogin, SecurityEventTokenRefresh, SecurityEventLogout, SecurityEventPasswordChange,
+	SecurityEventImpersonation,
+}
+
+func (t SecurityEventType) IsValid() bool {
+	for _, eventType := range ValidSecurityEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// IPAccessGroup adalah grup route yang dibatasi asal request-nya
+type IPAccessGroup string
+
+const (
+	IPAccessGroupAdmin      IPAccessGroup = "admin"       // /api/v1/admin
+	IPAccessGroupSuperAdmin IPAccessGroup = "super_admin" // /api/v1/super-admin
+)
@@ -7,27 +7,131 @@ import (
 )
 
 type User struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	Username     string    `json:"username" gorm:"unique;not null"`
-	Email        string    `json:"email" gorm:"unique;not null"`
-	Password     string    `json:"-" gorm:"not null"`                                     // Password tidak ditampilkan di JSON
-	Role         UserRole  `json:"role" gorm:"type:varchar(20);not null;default:'admin'"` // Role user
-	RefreshToken string    `json:"-"`                                                     // Refresh token untuk login
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 uint       `json:"id" gorm:"primaryKey"`
+	Username           string     `json:"username" gorm:"unique;not null"`
+	Email              string     `json:"email" gorm:"unique;not null"`
+	Password           string     `json:"-" gorm:"not null"`                                     // Password tidak ditampilkan di JSON
+	Role               UserRole   `json:"role" gorm:"type:varchar(50);not null;default:'admin'"` // Nama role, lihat tabel roles
+	IsActive           bool       `json:"is_active" gorm:"not null;default:true"`                // Akun nonaktif tidak bisa login
+	TokenVersion       uint       `json:"-" gorm:"not null;default:0"`                           // Claim ver, dinaikkan untuk mencabut semua token user
+	TOTPSecret         string     `json:"-" gorm:"type:varchar(64)"`                             // Secret TOTP (base32)
+	TOTPEnabled        bool       `json:"two_factor_enabled" gorm:"not null;default:false"`      // 2FA aktif setelah kode pertama diverifikasi
+	TOTPLastStep       int64      `json:"-" gorm:"not null;default:0"`                           // Time step terakhir yang dipakai, mencegah replay
+	MustChangePassword bool       `json:"must_change_password" gorm:"not null;default:false"`    // Semua route diblokir sampai password diganti
+	PasskeyEnabled     bool       `json:"passkey_enabled" gorm:"not null;default:false"`         // Punya minimal satu passkey, bisa dipakai sebagai faktor kedua
+	LastLoginAt        *time.Time `json:"last_login_at"`                                         // Login berhasil terakhir
+	LastLoginIP        string     `json:"last_login_ip" gorm:"type:varchar(45)"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts a user into its public API representation
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:                 u.ID,
+		Username:           u.Username,
+		Email:              u.Email,
+		Role:               u.Role,
+		IsActive:           u.IsActive,
+		CreatedAt:          u.CreatedAt,
+		TwoFactorEnabled:   u.TOTPEnabled,
+		MustChangePassword: u.MustChangePassword,
+		PasskeyEnabled:     u.PasskeyEnabled,
+		LastLoginAt:        u.LastLoginAt,
+		LastLoginIP:        u.LastLoginIP,
+	}
+}
+
+// HasSecondFactor reports whether login needs a TOTP code or passkey after the first step
+func (u *User) HasSecondFactor() bool {
+	return u.TOTPEnabled || u.PasskeyEnabled
 }
 
 type News struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	NewsTitle string         `json:"news_title" gorm:"not null"`                                // Judul berita
-	Slug      string         `json:"slug" gorm:"unique;not null;index"`                         // URL slug untuk berita
-	Category  string         `json:"category" gorm:"not null"`                                  // Kategori berita
-	Status    NewsStatus     `json:"status" gorm:"type:varchar(20);not null;default:'Drafted'"` // Status publish
-	Content   string         `json:"content" gorm:"type:text"`                                  // Isi berita
-	Image     string         `json:"image"`                                                     // Gambar berita
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	NewsTitle       string         `json:"news_title" gorm:"type:text;not null;index:ft_news,class:FULLTEXT"` // Judul berita
+	Slug            string         `json:"slug" gorm:"unique;not null;index"`                                 // URL slug untuk berita
+	Category        string         `json:"category" gorm:"not null"`                                          // Nama kategori, disalin dari tabel categories
+	CategoryID      *uint          `json:"category_id" gorm:"index"`                                          // Kosong hanya untuk data lama yang belum dinormalisasi
+	CategoryDetail  *Category      `json:"category_detail,omitempty" gorm:"foreignKey:CategoryID"`            // Slug, deskripsi dan urutan kategori
+	Tags            []Tag          `json:"tags" gorm:"many2many:news_tags"`                                   // Tag berita, lewat tabel news_tags
+	Status          NewsStatus     `json:"status" gorm:"type:varchar(20);not null;default:'Drafted'"`         // Status publish
+	Content         string         `json:"content" gorm:"type:text;index:ft_news,class:FULLTEXT"`             // Isi berita, bersama judul diindeks untuk pencarian
+	Image           string         `json:"image"`                                                             // Gambar berita
+	AuthorID        *uint          `json:"author_id" gorm:"index"`                                            // Pembuat berita, untuk permission news:edit_own
+	PublishAt       *time.Time     `json:"publish_at" gorm:"index"`                                           // Waktu tayang (jadwal, atau saat dipublish manual)
+	UnpublishAt     *time.Time     `json:"unpublish_at" gorm:"index"`                                         // Diarsipkan otomatis setelah waktu ini, kosong = tayang terus
+	LegalAdvice     bool           `json:"legal_advice" gorm:"not null;default:false"`                        // Berisi nasihat hukum, wajib disetujui partner sebelum tayang
+	LegalApprovedBy *uint          `json:"legal_approved_by"`                                                 // Partner yang menyetujui, kosong lagi jika dikembalikan ke draft
+	LegalApprovedAt *time.Time     `json:"legal_approved_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
+}
+
+// Category adalah kategori berita. Satu berita punya satu kategori.
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"`
+	Slug        string    `json:"slug" gorm:"type:varchar(120);uniqueIndex;not null"` // Dipakai di filter ?category=
+	Description string    `json:"description" gorm:"type:text"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"` // Urutan tampil, kecil dulu
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Tag adalah label berita. Satu berita bisa punya banyak tag.
+type Tag struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"`
+	Slug        string    `json:"slug" gorm:"type:varchar(120);uniqueIndex;not null"` // Dipakai di filter ?tag=
+	Description string    `json:"description" gorm:"type:text"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryCount adalah kategori beserta jumlah beritanya
+type CategoryCount struct {
+	Category
+	NewsCount int64 `json:"news_count"`
+}
+
+// TagCount adalah tag beserta jumlah beritanya
+type TagCount struct {
+	Tag
+	NewsCount int64 `json:"news_count"`
+}
+
+// NewsRevision adalah salinan berita yang tidak pernah diubah, dibuat setiap kali berita
+// disimpan. Dipakai untuk melihat riwayat, membandingkan dan mengembalikan isi lama.
+type NewsRevision struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	NewsID         uint       `json:"news_id" gorm:"not null;uniqueIndex:idx_news_revision"`
+	Revision       int        `json:"revision" gorm:"not null;uniqueIndex:idx_news_revision"` // Nomor urut per berita, mulai dari 1
+	NewsTitle      string     `json:"news_title" gorm:"not null"`
+	Slug           string     `json:"slug"`
+	Category       string     `json:"category"`
+	CategoryID     *uint      `json:"category_id"`
+	Status         NewsStatus `json:"status" gorm:"type:varchar(20)"`
+	Content        string     `json:"content,omitempty" gorm:"type:text"` // Kosong di daftar revisi
+	Image          string     `json:"image"`
+	EditorID       *uint      `json:"editor_id" gorm:"index"`                   // Kosong untuk perubahan oleh sistem
+	EditorUsername string     `json:"editor_username" gorm:"type:varchar(255)"` // Juga berisi actor sistem, mis. system:news_scheduler
+	Note           string     `json:"note" gorm:"type:varchar(100)"`            // create, update, publish, schedule, restore dari revisi N
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewsTransition adalah satu perpindahan status berita di workflow redaksi
+type NewsTransition struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	NewsID        uint       `json:"news_id" gorm:"not null;index"`
+	FromStatus    NewsStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus      NewsStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	Comment       string     `json:"comment" gorm:"type:text"`                // Wajib saat reviewer mengembalikan berita
+	ActorID       *uint      `json:"actor_id" gorm:"index"`                   // Kosong untuk perubahan oleh sistem
+	ActorUsername string     `json:"actor_username" gorm:"type:varchar(255)"` // Juga berisi actor sistem, mis. system:news_scheduler
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
 type Member struct {
@@ -49,13 +153,236 @@ type Member struct {
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
 }
 
+// Role adalah kumpulan permission. Role bawaan dibuat saat startup, role lain bisa ditambah oleh super admin.
+type Role struct {
+	Name        UserRole     `json:"name" gorm:"primaryKey;type:varchar(50)"`
+	Description string       `json:"description" gorm:"type:varchar(255)"`
+	Permissions []Permission `json:"permissions" gorm:"serializer:json;type:text"`
+	IsBuiltIn   bool         `json:"is_built_in" gorm:"not null;default:false"` // Role bawaan tidak bisa dihapus
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// BlacklistedToken adalah token yang dicabut sebelum expire. Dicek dari cache in-process
+// (lihat TokenRevocationList), tabel ini hanya sumber data cache tersebut.
 type BlacklistedToken struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
-	Token     string    `json:"token" gorm:"not null;type:text"`                   // JWT token yang di-blacklist (full token)
+	Token     string    `json:"-" gorm:"type:text"`                                // Tidak diisi lagi, token asli tidak perlu disimpan
 	TokenHash string    `json:"token_hash" gorm:"not null;index;type:varchar(64)"` // SHA256 hash of token for indexing
+	JTI       string    `json:"jti" gorm:"type:varchar(64);index"`                 // jti token, kosong jika token tidak bisa di-parse
 	UserID    uint      `json:"user_id" gorm:"not null"`                           // ID user yang logout
 	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`                  // Kapan token expire
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`                           // Dipakai untuk sync cache antar instance
+}
+
+// Session mewakili satu login (token family). Setiap refresh merotasi RefreshJTI;
+// refresh token lama yang dipakai ulang akan mencabut seluruh sesi.
+type Session struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	FamilyID       string     `json:"-" gorm:"not null;uniqueIndex;type:varchar(64)"` // Disimpan di claim sid
+	UserID         uint       `json:"user_id" gorm:"not null;index"`
+	RefreshJTI     string     `json:"-" gorm:"not null;type:varchar(64)"` // jti refresh token yang masih berlaku
+	UserAgent      string     `json:"user_agent" gorm:"type:varchar(512)"`
+	IPAddress      string     `json:"ip_address" gorm:"type:varchar(45)"`
+	LastUsedAt     time.Time  `json:"last_used_at"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	RevokedReason  string     `json:"revoked_reason,omitempty" gorm:"type:varchar(50)"`
+	ImpersonatorID *uint      `json:"impersonator_id,omitempty" gorm:"index"` // Super admin yang menyamar sebagai user, sesi tanpa refresh token
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ClientInfo berisi informasi perangkat dari request yang sedang diproses
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// APIKey dipakai mesin (build server, script sinkronisasi) untuk mengakses API tanpa login sebagai admin.
+// Hanya hash key yang disimpan, key asli ditampilkan sekali saat dibuat.
+type APIKey struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"not null;type:varchar(100)"`
+	Prefix      string       `json:"prefix" gorm:"not null;type:varchar(16)"`        // Awal key untuk identifikasi, mis. hsk_AbCd1234
+	KeyHash     string       `json:"-" gorm:"not null;uniqueIndex;type:varchar(64)"` // SHA256 hash dari key
+	Scopes      []Permission `json:"scopes" gorm:"serializer:json;type:text"`        // Permission yang boleh dipakai key
+	AllowedIPs  []string     `json:"allowed_ips" gorm:"serializer:json;type:text"`   // IP atau CIDR, kosong = semua IP
+	ExpiresAt   *time.Time   `json:"expires_at"`
+	LastUsedAt  *time.Time   `json:"last_used_at"`
+	LastUsedIP  string       `json:"last_used_ip" gorm:"type:varchar(45)"`
+	CreatedByID uint         `json:"created_by_id" gorm:"not null;index"`
+	RevokedAt   *time.Time   `json:"revoked_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// AuditLog mencatat siapa mengubah apa dan kapan. Changes berisi field yang berubah (before/after).
+type AuditLog struct {
+	ID                   uint                   `json:"id" gorm:"primaryKey"`
+	ActorID              *uint                  `json:"actor_id" gorm:"index"`                   // Kosong untuk login gagal
+	ActorUsername        string                 `json:"actor_username" gorm:"type:varchar(255)"` // Disimpan agar tetap terbaca setelah user dihapus
+	APIKeyID             *uint                  `json:"api_key_id,omitempty"`                    // Terisi jika request memakai API key
+	ImpersonatorID       *uint                  `json:"impersonator_id,omitempty" gorm:"index"`  // Super admin yang menyamar sebagai actor
+	ImpersonatorUsername string                 `json:"impersonator_username,omitempty" gorm:"type:varchar(255)"`
+	Action               AuditAction            `json:"action" gorm:"type:varchar(20);not null;index"` // create, update, delete, publish, login, ...
+	EntityType           string                 `json:"entity_type" gorm:"type:varchar(50);index:idx_audit_entity"`
+	EntityID             string                 `json:"entity_id" gorm:"type:varchar(64);index:idx_audit_entity"`
+	TraceID              string                 `json:"trace_id" gorm:"type:varchar(64);index"` // Dari TraceIDMiddleware
+	IPAddress            string                 `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent            string                 `json:"user_agent" gorm:"type:varchar(512)"`
+	Changes              map[string]AuditChange `json:"changes,omitempty" gorm:"serializer:json;type:longtext"`
+	CreatedAt            time.Time              `json:"created_at" gorm:"index"`
+}
+
+// SecurityEvent adalah satu kejadian di riwayat keamanan user: login (berhasil atau gagal),
+// refresh token, logout dan ganti password. Dipakai untuk mendeteksi akun yang dibobol.
+type SecurityEvent struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	UserID    *uint             `json:"user_id" gorm:"index:idx_security_user_time"` // Kosong jika username tidak dikenal
+	Username  string            `json:"username" gorm:"type:varchar(255)"`           // Username yang dipakai, juga untuk login gagal
+	Type      SecurityEventType `json:"type" gorm:"type:varchar(30);not null;index"`
+	Success   bool              `json:"success" gorm:"not null"`
+	Method    string            `json:"method,omitempty" gorm:"type:varchar(20)"`  // password, totp, passkey, sso, reset
+	Detail    string            `json:"detail,omitempty" gorm:"type:varchar(255)"` // Alasan gagal, mis. invalid password
+	IPAddress string            `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent string            `json:"user_agent" gorm:"type:varchar(512)"`
+	TraceID   string            `json:"trace_id" gorm:"type:varchar(64);index"` // Dari TraceIDMiddleware
+	CreatedAt time.Time         `json:"created_at" gorm:"index:idx_security_user_time"`
+}
+
+// SecurityEventFilter adalah filter riwayat keamanan, semua field opsional
+type SecurityEventFilter struct {
+	Type    SecurityEventType
+	Success *bool
+	From    *time.Time
+	To      *time.Time
+}
+
+// AuditChange adalah nilai satu field sebelum dan sesudah perubahan
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditLogFilter adalah filter query audit log, semua field opsional
+type AuditLogFilter struct {
+	ActorID        uint
+	ImpersonatorID uint
+	EntityType     string
+	EntityID       string
+	Action         AuditAction
+	From           *time.Time
+	To             *time.Time
+}
+
+// PasswordHistory menyimpan hash password terakhir user agar password lama tidak dipakai ulang
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`
+	PasswordHash string    `json:"-" gorm:"not null;type:varchar(255)"` // bcrypt hash
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Passkey adalah credential WebAuthn milik user
+type Passkey struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	UserID            uint       `json:"user_id" gorm:"not null;index"`
+	Name              string     `json:"name" gorm:"type:varchar(100)"`                               // Nama perangkat, dipilih user
+	CredentialID      string     `json:"credential_id" gorm:"not null;uniqueIndex;type:varchar(512)"` // base64url
+	PublicKey         []byte     `json:"-" gorm:"not null;type:blob"`                                 // COSE_Key
+	Algorithm         int64      `json:"algorithm" gorm:"not null"`                                   // COSE algorithm, mis. -7 (ES256)
+	SignCount         uint32     `json:"-" gorm:"not null;default:0"`                                 // Counter terakhir, untuk mendeteksi authenticator yang di-clone
+	AAGUID            string     `json:"aaguid" gorm:"type:varchar(36)"`                              // Model authenticator
+	AttestationFormat string     `json:"attestation_format" gorm:"type:varchar(32)"`
+	Transports        []string   `json:"transports" gorm:"serializer:json;type:varchar(255)"`
+	LastUsedAt        *time.Time `json:"last_used_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// UserIdentity menghubungkan user lokal dengan akun di identity provider (SSO)
+type UserIdentity struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	Issuer      string     `json:"issuer" gorm:"not null;type:varchar(255);uniqueIndex:idx_identity_issuer_subject"`
+	Subject     string     `json:"subject" gorm:"not null;type:varchar(255);uniqueIndex:idx_identity_issuer_subject"` // Claim sub dari ID token
+	Email       string     `json:"email" gorm:"type:varchar(100)"`                                                    // Email saat identitas dihubungkan
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Invitation mengundang admin baru lewat email. Password dipilih sendiri oleh yang diundang.
+type Invitation struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	Email          string           `json:"email" gorm:"not null;index;type:varchar(100)"`
+	Role           UserRole         `json:"role" gorm:"not null;type:varchar(50)"`
+	TokenHash      string           `json:"-" gorm:"not null;uniqueIndex;type:varchar(64)"` // SHA256 hash dari token terakhir yang dikirim
+	InvitedByID    uint             `json:"invited_by_id" gorm:"not null;index"`
+	ExpiresAt      time.Time        `json:"expires_at" gorm:"not null"`
+	SentCount      int              `json:"sent_count" gorm:"not null;default:1"`
+	LastSentAt     time.Time        `json:"last_sent_at"`
+	AcceptedAt     *time.Time       `json:"accepted_at"`
+	AcceptedUserID *uint            `json:"accepted_user_id"`
+	RevokedAt      *time.Time       `json:"revoked_at"`
+	Status         InvitationStatus `json:"status" gorm:"-"` // Dihitung dari accepted_at, revoked_at dan expires_at
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// CurrentStatus derives the status of the invitation at the given time
+func (i *Invitation) CurrentStatus(now time.Time) InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case now.After(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+type PasswordResetToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`                  // Pemilik token
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex;type:varchar(64)"` // SHA256 hash dari token reset
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`               // Kapan token expire
+	UsedAt    *time.Time `json:"used_at"`                                        // Kapan token dipakai (sekali pakai)
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`            // Pemilik kode
+	CodeHash  string     `json:"-" gorm:"not null;index;type:varchar(64)"` // SHA256 hash dari recovery code
+	UsedAt    *time.Time `json:"used_at"`                                  // Kode hanya bisa dipakai sekali
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SystemSetting menyimpan pengaturan aplikasi yang bisa diubah tanpa restart
+type SystemSetting struct {
+	Key       string    `json:"key" gorm:"primaryKey;type:varchar(100)"`
+	Value     string    `json:"value" gorm:"type:text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IPAccessRule membatasi asal request ke satu grup route. List kosong = tidak dibatasi.
+type IPAccessRule struct {
+	AllowedIPs       []string `json:"allowed_ips"`       // IP atau CIDR, mis. range VPN kantor
+	AllowedCountries []string `json:"allowed_countries"` // Kode negara ISO 3166-1 alpha-2 dari GEO_COUNTRY_HEADER
+}
+
+// IPAccessRules disimpan di system_settings, default dari env ADMIN_ALLOWED_* dan SUPER_ADMIN_ALLOWED_*
+type IPAccessRules struct {
+	Admin      IPAccessRule `json:"admin"`
+	SuperAdmin IPAccessRule `json:"super_admin"`
+}
+
+// For returns the rule of the route group
+func (r *IPAccessRules) For(group IPAccessGroup) IPAccessRule {
+	if group == IPAccessGroupSuperAdmin {
+		return r.SuperAdmin
+	}
+	return r.Admin
 }
 
 type LoginRequest struct {
@@ -71,26 +398,263 @@ type TokenResponse struct {
 }
 
 type UserResponse struct {
-	ID       uint     `json:"id"`
-	Username string   `json:"username"`
-	Email    string   `json:"email"`
-	Role     UserRole `json:"role"`
+	ID                 uint       `json:"id"`
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	Role               UserRole   `json:"role"`
+	IsActive           bool       `json:"is_active"`
+	CreatedAt          time.Time  `json:"created_at"`
+	TwoFactorEnabled   bool       `json:"two_factor_enabled"`
+	MustChangePassword bool       `json:"must_change_password"`
+	PasskeyEnabled     bool       `json:"passkey_enabled"`
+	LastLoginAt        *time.Time `json:"last_login_at"`
+	LastLoginIP        string     `json:"last_login_ip"`
+}
+
+type SessionResponse struct {
+	ID             uint      `json:"id"`
+	UserAgent      string    `json:"user_agent"`
+	IPAddress      string    `json:"ip_address"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastUsedAt     time.Time `json:"last_used_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	Current        bool      `json:"current"`
+	ImpersonatorID *uint     `json:"impersonator_id,omitempty"` // Sesi impersonation oleh super admin
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
+// Aturan password (panjang, jenis karakter, dll.) dicek oleh PasswordPolicy, bukan binding
 type CreateAdminRequest struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
+}
+
+// PasskeyCredential adalah PublicKeyCredential dari browser, semua nilai biner dalam base64url
+type PasskeyCredential struct {
+	ID       string                       `json:"id" binding:"required"`
+	Type     string                       `json:"type" binding:"required,eq=public-key"`
+	Response PasskeyAuthenticatorResponse `json:"response" binding:"required"`
+}
+
+type PasskeyAuthenticatorResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject,omitempty"` // Registrasi
+	Transports        []string `json:"transports,omitempty"`        // Registrasi
+	AuthenticatorData string   `json:"authenticatorData,omitempty"` // Login
+	Signature         string   `json:"signature,omitempty"`         // Login
+	UserHandle        string   `json:"userHandle,omitempty"`        // Login tanpa password
+}
+
+type PasskeyRegistrationRequest struct {
+	SessionToken string            `json:"session_token" binding:"required"`
+	Name         string            `json:"name" binding:"max=100"`
+	Credential   PasskeyCredential `json:"credential" binding:"required"`
+}
+
+// PasskeyLoginBeginRequest: challenge_token diisi jika passkey dipakai sebagai faktor kedua setelah password
+type PasskeyLoginBeginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type PasskeyLoginRequest struct {
+	SessionToken string            `json:"session_token" binding:"required"`
+	Credential   PasskeyCredential `json:"credential" binding:"required"`
+}
+
+type CreateInvitationRequest struct {
+	Email string   `json:"email" binding:"required,email"`
+	Role  UserRole `json:"role" binding:"required"`
+}
+
+// AcceptInvitationRequest dipakai oleh admin yang diundang untuk membuat akunnya sendiri
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
 
 type UpdateProfileRequest struct {
+	Username        string `json:"username" binding:"required"`
+	Email           string `json:"email" binding:"required,email"`
+	Password        string `json:"password,omitempty"`
+	CurrentPassword string `json:"current_password,omitempty" binding:"required_with=Password"` // Wajib jika password diganti
+}
+
+type UpdateAdminRequest struct {
+	Username string `json:"username" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+type ChangeRoleRequest struct {
+	Role UserRole `json:"role" binding:"required"`
+}
+
+// TaxonomyRequest membuat atau mengganti kategori / tag. Slug kosong dibuat dari nama.
+type TaxonomyRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=120"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// NewsFilter adalah filter daftar berita, semua field opsional. Category dan Tag
+// menerima slug atau nama.
+type NewsFilter struct {
+	Category string
+	Tag      string
+	Status   NewsStatus // Hanya untuk daftar admin
+	From     *time.Time // Tanggal tayang (publish_at, atau created_at jika kosong) mulai dari
+	To       *time.Time // Tanggal tayang sebelum
+}
+
+// NewsSearchResult adalah berita hasil pencarian beserta skor relevansi dan potongan
+// teks yang cocok. TitleHighlight dan Snippet sudah di-escape, kata yang cocok diberi <mark>.
+type NewsSearchResult struct {
+	News
+	Relevance      float64 `json:"relevance"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type CreateRoleRequest struct {
+	Name        UserRole     `json:"name" binding:"required,max=50"`
+	Description string       `json:"description" binding:"max=255"`
+	Permissions []Permission `json:"permissions" binding:"required"`
+}
+
+type UpdateRoleRequest struct {
+	Description string       `json:"description" binding:"max=255"`
+	Permissions []Permission `json:"permissions" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name       string       `json:"name" binding:"required,max=100"`
+	Scopes     []Permission `json:"scopes" binding:"required,min=1"`
+	AllowedIPs []string     `json:"allowed_ips"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+}
+
+// CreatedAPIKeyResponse berisi key asli, hanya dikirim sekali saat key dibuat
+type CreatedAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// StartImpersonationRequest memulai sesi super admin sebagai admin lain untuk reproduksi masalah
+type StartImpersonationRequest struct {
+	UserID           uint   `json:"user_id" binding:"required"`
+	Reason           string `json:"reason" binding:"required,max=255"` // Dicatat di audit log
+	DurationMinutes  int    `json:"duration_minutes" binding:"min=0"`  // 0 = maksimum dari IMPERSONATION_TTL
+	AllowDestructive bool   `json:"allow_destructive"`                 // Izinkan request DELETE selama impersonation
+}
+
+// ImpersonationResponse berisi access token impersonation, tanpa refresh token
+type ImpersonationResponse struct {
+	AccessToken      string        `json:"access_token"`
+	ExpiresAt        time.Time     `json:"expires_at"`
+	SessionID        uint          `json:"session_id"`
+	AllowDestructive bool          `json:"allow_destructive"`
+	User             *UserResponse `json:"user"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// SetupRequest membuat super admin pertama dengan setup token yang dicetak saat startup
+type SetupRequest struct {
+	Token    string `json:"token" binding:"required"`
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password,omitempty" binding:"omitempty,min=6"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // Kode TOTP atau recovery code
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type IPAccessRulesRequest struct {
+	Admin      *IPAccessRule `json:"admin" binding:"required"`
+	SuperAdmin *IPAccessRule `json:"super_admin" binding:"required"`
+}
+
+type TwoFactorPolicyRequest struct {
+	RequireForSuperAdmins *bool `json:"require_for_super_admins" binding:"required"`
+}
+
+// ScheduleNewsRequest menjadwalkan berita. Waktu dalam RFC 3339 dengan zona waktu,
+// mis. 2026-01-05T09:00:00+07:00 untuk 09:00 WIB.
+type ScheduleNewsRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`   // Wajib untuk draft, diabaikan untuk berita yang sudah tayang
+	UnpublishAt *time.Time `json:"unpublish_at"` // Opsional, harus setelah publish_at
+}
+
+// NewsTransitionRule adalah satu perpindahan status yang diizinkan di workflow redaksi
+type NewsTransitionRule struct {
+	From           NewsStatus   `json:"from" binding:"required"`
+	To             NewsStatus   `json:"to" binding:"required"`
+	Permissions    []Permission `json:"permissions" binding:"required,min=1"` // Cukup salah satu, news:edit_own hanya berlaku untuk penulis berita
+	RequireComment bool         `json:"require_comment"`                      // Komentar wajib diisi, mis. saat meminta perubahan
+}
+
+// NewsWorkflow adalah daftar transisi status berita. Scheduled -> Posted dan Posted -> Archived
+// oleh scheduler selalu berjalan, terlepas dari daftar ini.
+type NewsWorkflow struct {
+	Transitions []NewsTransitionRule `json:"transitions" binding:"required,min=1,dive"`
+}
+
+// NewsTransitionRequest memindahkan berita ke status lain
+type NewsTransitionRequest struct {
+	Status      NewsStatus `json:"status" binding:"required"`
+	Comment     string     `json:"comment"`
+	LegalAdvice *bool      `json:"legal_advice"` // Opsional, menandai berita berisi nasihat hukum. Menghapus tanda butuh news:approve_legal
+}
+
+// NewsFieldChange adalah satu field yang berbeda di antara dua revisi
+type NewsFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// DiffLine adalah satu baris hasil perbandingan teks: equal, insert atau delete
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// NewsRevisionDiff adalah perbandingan dua revisi berita
+type NewsRevisionDiff struct {
+	NewsID         uint              `json:"news_id"`
+	From           *NewsRevision     `json:"from"`
+	To             *NewsRevision     `json:"to"`
+	Fields         []NewsFieldChange `json:"fields"`          // Field selain content yang berubah
+	ContentChanged bool              `json:"content_changed"` // Detail per baris ada di Content
+	Content        []DiffLine        `json:"content"`
 }
 
 type NewsRequest struct {
@@ -8,8 +8,8 @@ import (
 )
 
 type BlacklistRepository interface {
-	AddToBlacklist(token string, userID uint, expiresAt time.Time) error
-	IsTokenBlacklisted(token string) (bool, error)
+	Add(entry *models.BlacklistedToken) error
+	GetActiveSince(since time.Time) ([]models.BlacklistedToken, error)
 	CleanupExpiredTokens() error
 }
 
@@ -21,26 +21,17 @@ func NewBlacklistRepository(db *gorm.DB) BlacklistRepository {
 	return &blacklistRepository{db: db}
 }
 
-func (r *blacklistRepository) AddToBlacklist(token string, userID uint, expiresAt time.Time) error {
-	blacklistedToken := &models.BlacklistedToken{
-		Token:     token,
-		UserID:    userID,
-		ExpiresAt: expiresAt,
-	}
-	return r.db.Create(blacklistedToken).Error
+func (r *blacklistRepository) Add(entry *models.BlacklistedToken) error {
+	return r.db.Create(entry).Error
 }
 
-func (r *blacklistRepository) IsTokenBlacklisted(token string) (bool, error) {
-	var count int64
-	err := r.db.Model(&models.BlacklistedToken{}).
-		Where("token = ? AND expires_at > ?", token, time.Now()).
-		Count(&count).Error
-
-	if err != nil {
-		return false, err
-	}
-
-	return count > 0, nil
+// GetActiveSince returns unexpired entries created at or after since. A zero since returns every unexpired entry.
+func (r *blacklistRepository) GetActiveSince(since time.Time) ([]models.BlacklistedToken, error) {
+	var entries []models.BlacklistedToken
+	err := r.db.Select("id, token_hash, jti, expires_at, created_at").
+		Where("expires_at > ? AND created_at >= ?", time.Now(), since).
+		Find(&entries).Error
+	return entries, err
 }
 
 func (r *blacklistRepository) CleanupExpiredTokens() error {
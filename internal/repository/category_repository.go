@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LegacyCategory is a free-text category still stored on news without a category_id
+type LegacyCategory struct {
+	Name  string
+	Count int64
+}
+
+type CategoryRepository interface {
+	List() ([]models.Category, error)
+	GetByID(id uint) (*models.Category, error)
+	FindByNameOrSlug(name, slug string) (*models.Category, error)
+	Taken(name, slug string, exceptID uint) (bool, error)
+	Create(category *models.Category) error
+	Update(category *models.Category) error
+	Delete(id uint, moveTo *models.Category) error
+	CountNews(publishedOnly bool) (map[uint]int64, error)
+	CountNewsInCategory(id uint) (int64, error)
+	ListLegacy() ([]LegacyCategory, error)
+	AssignLegacy(category *models.Category, names []string) (int64, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) List() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("sort_order ASC, name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
+	var category models.Category
+	err := r.db.First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindByNameOrSlug looks up the slug first, then the name (case-insensitive with the
+// MySQL collation)
+func (r *categoryRepository) FindByNameOrSlug(name, slug string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.Where("name = ?", name).First(&category).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Taken reports whether another category than exceptID already uses the name or the slug
+func (r *categoryRepository) Taken(name, slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("(name = ? OR slug = ?) AND id <> ?", name, slug, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+// Update saves the category and copies a new name onto its news, which keeps the name
+// for clients that read news.category
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.News{}).Where("category_id = ?", category.ID).
+			UpdateColumn("category", category.Name).Error
+	})
+}
+
+// Delete removes the category. News in it, including soft-deleted news, moves to moveTo first.
+func (r *categoryRepository) Delete(id uint, moveTo *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if moveTo != nil {
+			if err := tx.Unscoped().Model(&models.News{}).Where("category_id = ?", id).
+				UpdateColumns(map[string]interface{}{"category_id": moveTo.ID, "category": moveTo.Name}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Category{}, id).Error
+	})
+}
+
+// CountNews counts news per category, only news that is live now when publishedOnly is set
+func (r *categoryRepository) CountNews(publishedOnly bool) (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+
+	query := r.db.Model(&models.News{}).Select("category_id, COUNT(*) AS count").
+		Where("category_id IS NOT NULL").Group("category_id")
+	if publishedOnly {
+		query = query.Scopes(publishedAt(time.Now()))
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountNewsInCategory includes soft-deleted news, which still reference the category
+func (r *categoryRepository) CountNewsInCategory(id uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.News{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ListLegacy returns the free-text categories of news without a category_id, most used first
+func (r *categoryRepository) ListLegacy() ([]LegacyCategory, error) {
+	var legacy []LegacyCategory
+	err := r.db.Unscoped().Model(&models.News{}).Select("category AS name, COUNT(*) AS count").
+		Where("category_id IS NULL").Group("category").Order("count DESC, name ASC").
+		Scan(&legacy).Error
+	return legacy, err
+}
+
+// AssignLegacy links news whose free-text category is one of names to the category
+func (r *categoryRepository) AssignLegacy(category *models.Category, names []string) (int64, error) {
+	result := r.db.Unscoped().Model(&models.News{}).Where("category_id IS NULL AND category IN ?", names).
+		UpdateColumns(map[string]interface{}{"category_id": category.ID, "category": category.Name})
+	return result.RowsAffected, result.Error
+}
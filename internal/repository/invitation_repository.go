@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	Create(invitation *models.Invitation) error
+	GetByID(id uint) (*models.Invitation, error)
+	GetByTokenHash(tokenHash string) (*models.Invitation, error)
+	GetOpenByEmail(email string) (*models.Invitation, error)
+	ListOpen() ([]models.Invitation, error)
+	Update(invitation *models.Invitation) error
+	MarkAccepted(id uint, tokenHash string) (bool, error)
+	Reopen(id uint) error
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(invitation *models.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *invitationRepository) GetByID(id uint) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) GetByTokenHash(tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetOpenByEmail returns the invitation for the email that was neither accepted nor revoked, expired or not
+func (r *invitationRepository) GetOpenByEmail(email string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.Where("email = ? AND accepted_at IS NULL AND revoked_at IS NULL", email).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) ListOpen() ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.Where("accepted_at IS NULL AND revoked_at IS NULL").
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *invitationRepository) Update(invitation *models.Invitation) error {
+	return r.db.Save(invitation).Error
+}
+
+// MarkAccepted atomically consumes the invitation, returns false if it was already
+// accepted, revoked, expired or resent with a newer token
+func (r *invitationRepository) MarkAccepted(id uint, tokenHash string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.Invitation{}).
+		Where("id = ? AND token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, tokenHash, now).
+		Update("accepted_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Reopen undoes MarkAccepted when the account could not be created
+func (r *invitationRepository) Reopen(id uint) error {
+	return r.db.Model(&models.Invitation{}).
+		Where("id = ? AND accepted_user_id IS NULL", id).
+		Update("accepted_at", nil).Error
+}
@@ -2,21 +2,36 @@ package repository
 
 import (
 	"haslaw-be-services/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// newsListColumns are the columns loaded for list views
+const newsListColumns = "id, news_title, slug, category, category_id, status, content, image, author_id, publish_at, unpublish_at, legal_advice, legal_approved_by, legal_approved_at, created_at, updated_at"
+
+// newsMatch scores news against a search query with the ft_news full-text index
+const newsMatch = "MATCH(news_title, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
 type NewsRepository interface {
 	Create(news *models.News) error
-	GetAll(limit, offset int, orderBy string) ([]models.News, int64, error)
-	GetPublished(limit, offset int, orderBy string, category string) ([]models.News, int64, error)
+	GetAll(limit, offset int, orderBy string, filter *models.NewsFilter) ([]models.News, int64, error)
+	GetPublished(limit, offset int, orderBy string, filter *models.NewsFilter) ([]models.News, int64, error)
+	GetPublishedByID(id uint) (*models.News, error)
+	GetPublishedBySlug(slug string) (*models.News, error)
 	GetDrafts(limit, offset int, orderBy string) ([]models.News, int64, error)
 	GetByID(id uint) (*models.News, error)
 	GetBySlug(slug string) (*models.News, error)
 	Update(news *models.News) error
+	ReplaceTags(news *models.News, tags []models.Tag) error
 	Delete(id uint) error
-	Publish(id uint) error
 	GetByCategory(category string, limit, offset int) ([]models.News, int64, error)
+	Search(query string, limit, offset int, filter *models.NewsFilter, publishedOnly bool) ([]models.NewsSearchResult, int64, error)
+	GetDueForPublish(now time.Time) ([]models.News, error)
+	GetDueForArchive(now time.Time) ([]models.News, error)
+	PublishScheduled(id uint, now time.Time) (bool, error)
+	ArchiveExpired(id uint, now time.Time) (bool, error)
 }
 
 type newsRepository struct {
@@ -27,16 +42,64 @@ func NewNewsRepository(db *gorm.DB) NewsRepository {
 	return &newsRepository{db: db}
 }
 
+// publishedAt limits a query to news that is live at now. Scheduled news counts as
+// live from publish_at, so it goes out on time even before the scheduler flips it.
+func publishedAt(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN ?", []models.NewsStatus{models.Posted, models.Scheduled}).
+			Where("(publish_at IS NULL AND status = ?) OR publish_at <= ?", models.Posted, now).
+			Where("unpublish_at IS NULL OR unpublish_at > ?", now)
+	}
+}
+
+// filtered applies the optional list filters. Category and tag match on slug or name,
+// the category also on the stored name for news that is not normalized yet.
+func filtered(filter *models.NewsFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter == nil {
+			return db
+		}
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.Category != "" {
+			db = db.Where("(category_id IN (SELECT id FROM categories WHERE slug = ? OR name = ?) OR (category_id IS NULL AND category = ?))",
+				filter.Category, filter.Category, filter.Category)
+		}
+		if filter.From != nil {
+			db = db.Where("COALESCE(publish_at, created_at) >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("COALESCE(publish_at, created_at) < ?", *filter.To)
+		}
+		if filter.Tag != "" {
+			db = db.Where("EXISTS (SELECT 1 FROM news_tags JOIN tags ON tags.id = news_tags.tag_id WHERE news_tags.news_id = news.id AND (tags.slug = ? OR tags.name = ?))",
+				filter.Tag, filter.Tag)
+		}
+		return db
+	}
+}
+
+// withTaxonomy loads the category and the tags of the news
+func withTaxonomy(db *gorm.DB) *gorm.DB {
+	return db.Preload("CategoryDetail").Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.sort_order ASC, tags.name ASC")
+	})
+}
+
+// Create stores the news only, tags are set with ReplaceTags
 func (r *newsRepository) Create(news *models.News) error {
-	return r.db.Create(news).Error
+	return r.db.Omit(clause.Associations).Create(news).Error
 }
 
-func (r *newsRepository) GetAll(limit, offset int, orderBy string) ([]models.News, int64, error) {
+// GetAll lists news of every status, narrowed by filter when it is set
+func (r *newsRepository) GetAll(limit, offset int, orderBy string, filter *models.NewsFilter) ([]models.News, int64, error) {
 	var news []models.News
 	var total int64
 
 	// Use single query with count estimation for better performance
-	query := r.db.Model(&models.News{})
+	query := r.db.Model(&models.News{}).Scopes(filtered(filter))
+	selectQuery := r.db.Select(newsListColumns).Scopes(filtered(filter), withTaxonomy)
 
 	// Perform count and select in parallel-like manner
 	countChan := make(chan error, 1)
@@ -45,7 +108,7 @@ func (r *newsRepository) GetAll(limit, offset int, orderBy string) ([]models.New
 	}()
 
 	// Execute main query with optimizations
-	err := r.db.Select("id, news_title, slug, category, status, content, image, created_at, updated_at").
+	err := selectQuery.
 		Offset(offset).
 		Limit(limit).
 		Order(orderBy).
@@ -59,15 +122,12 @@ func (r *newsRepository) GetAll(limit, offset int, orderBy string) ([]models.New
 	return news, total, err
 }
 
-func (r *newsRepository) GetPublished(limit, offset int, orderBy string, category string) ([]models.News, int64, error) {
+func (r *newsRepository) GetPublished(limit, offset int, orderBy string, filter *models.NewsFilter) ([]models.News, int64, error) {
 	var news []models.News
 	var total int64
 
-	baseQuery := r.db.Model(&models.News{}).Where("status = ?", models.Posted)
-
-	if category != "" {
-		baseQuery = baseQuery.Where("category = ?", category)
-	}
+	now := time.Now()
+	baseQuery := r.db.Model(&models.News{}).Scopes(publishedAt(now), filtered(filter))
 
 	// Parallel count and select
 	countChan := make(chan error, 1)
@@ -76,12 +136,8 @@ func (r *newsRepository) GetPublished(limit, offset int, orderBy string, categor
 	}()
 
 	// Optimized select query with limited fields for list view
-	selectQuery := r.db.Select("id, news_title, slug, category, status, content, image, created_at, updated_at").
-		Where("status = ?", models.Posted)
-
-	if category != "" {
-		selectQuery = selectQuery.Where("category = ?", category)
-	}
+	selectQuery := r.db.Select(newsListColumns).
+		Scopes(publishedAt(now), filtered(filter), withTaxonomy)
 
 	err := selectQuery.Offset(offset).
 		Limit(limit).
@@ -106,7 +162,7 @@ func (r *newsRepository) GetDrafts(limit, offset int, orderBy string) ([]models.
 		return nil, 0, err
 	}
 
-	if err := query.Offset(offset).Limit(limit).Order(orderBy).Find(&news).Error; err != nil {
+	if err := query.Scopes(withTaxonomy).Offset(offset).Limit(limit).Order(orderBy).Find(&news).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -115,7 +171,25 @@ func (r *newsRepository) GetDrafts(limit, offset int, orderBy string) ([]models.
 
 func (r *newsRepository) GetByID(id uint) (*models.News, error) {
 	var news models.News
-	err := r.db.First(&news, id).Error
+	err := r.db.Scopes(withTaxonomy).First(&news, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &news, nil
+}
+
+func (r *newsRepository) GetPublishedByID(id uint) (*models.News, error) {
+	var news models.News
+	err := r.db.Scopes(publishedAt(time.Now()), withTaxonomy).First(&news, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &news, nil
+}
+
+func (r *newsRepository) GetPublishedBySlug(slug string) (*models.News, error) {
+	var news models.News
+	err := r.db.Scopes(publishedAt(time.Now()), withTaxonomy).Where("slug = ?", slug).First(&news).Error
 	if err != nil {
 		return nil, err
 	}
@@ -124,38 +198,129 @@ func (r *newsRepository) GetByID(id uint) (*models.News, error) {
 
 func (r *newsRepository) GetBySlug(slug string) (*models.News, error) {
 	var news models.News
-	err := r.db.Where("slug = ?", slug).First(&news).Error
+	err := r.db.Scopes(withTaxonomy).Where("slug = ?", slug).First(&news).Error
 	if err != nil {
 		return nil, err
 	}
 	return &news, nil
 }
 
+// Update saves the news only, tags are set with ReplaceTags
 func (r *newsRepository) Update(news *models.News) error {
-	return r.db.Save(news).Error
+	return r.db.Omit(clause.Associations).Save(news).Error
 }
 
-func (r *newsRepository) Delete(id uint) error {
-	return r.db.Delete(&models.News{}, id).Error
+func (r *newsRepository) ReplaceTags(news *models.News, tags []models.Tag) error {
+	return r.db.Model(news).Association("Tags").Replace(tags)
 }
 
-func (r *newsRepository) Publish(id uint) error {
-	return r.db.Model(&models.News{}).Where("id = ?", id).Update("status", models.Posted).Error
+func (r *newsRepository) Delete(id uint) error {
+	return r.db.Delete(&models.News{}, id).Error
 }
 
 func (r *newsRepository) GetByCategory(category string, limit, offset int) ([]models.News, int64, error) {
 	var news []models.News
 	var total int64
 
-	query := r.db.Model(&models.News{}).Where("category = ? AND status = ?", category, models.Posted)
+	query := r.db.Model(&models.News{}).Scopes(publishedAt(time.Now()), filtered(&models.NewsFilter{Category: category}))
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&news).Error; err != nil {
+	if err := query.Scopes(withTaxonomy).Offset(offset).Limit(limit).Order("created_at DESC").Find(&news).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return news, total, nil
 }
+
+// Search returns the news matching query, most relevant first. Only news that is live
+// now is searched when publishedOnly is set.
+func (r *newsRepository) Search(query string, limit, offset int, filter *models.NewsFilter, publishedOnly bool) ([]models.NewsSearchResult, int64, error) {
+	var total int64
+
+	now := time.Now()
+	matching := func() *gorm.DB {
+		db := r.db.Model(&models.News{}).Where(newsMatch, query).Scopes(filtered(filter))
+		if publishedOnly {
+			db = db.Scopes(publishedAt(now))
+		}
+		return db
+	}
+
+	if err := matching().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Urutan dan skor dulu, lalu berita lengkap beserta kategori dan tag
+	var matches []struct {
+		ID        uint
+		Relevance float64
+	}
+	if err := matching().Select("id, "+newsMatch+" AS relevance", query).
+		Order("relevance DESC, COALESCE(publish_at, created_at) DESC").
+		Offset(offset).Limit(limit).Scan(&matches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]models.NewsSearchResult, 0, len(matches))
+	if len(matches) == 0 {
+		return results, total, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+
+	var news []models.News
+	if err := r.db.Select(newsListColumns).Scopes(withTaxonomy).Where("id IN ?", ids).Find(&news).Error; err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[uint]models.News, len(news))
+	for _, item := range news {
+		byID[item.ID] = item
+	}
+	for _, match := range matches {
+		if item, ok := byID[match.ID]; ok {
+			results = append(results, models.NewsSearchResult{News: item, Relevance: match.Relevance})
+		}
+	}
+
+	return results, total, nil
+}
+
+func (r *newsRepository) GetDueForPublish(now time.Time) ([]models.News, error) {
+	var news []models.News
+	err := r.db.Where("status = ? AND publish_at <= ?", models.Scheduled, now).
+		Order("publish_at ASC").
+		Find(&news).Error
+	return news, err
+}
+
+func (r *newsRepository) GetDueForArchive(now time.Time) ([]models.News, error) {
+	var news []models.News
+	err := r.db.Where("status = ? AND unpublish_at <= ?", models.Posted, now).
+		Order("unpublish_at ASC").
+		Find(&news).Error
+	return news, err
+}
+
+// PublishScheduled flips scheduled news to Posted only if it is still scheduled and due,
+// so a schedule cancelled in the meantime or another instance running the scheduler wins
+func (r *newsRepository) PublishScheduled(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.News{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, models.Scheduled, now).
+		Update("status", models.Posted)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ArchiveExpired archives posted news only if its unpublish time is still in the past
+func (r *newsRepository) ArchiveExpired(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.News{}).
+		Where("id = ? AND status = ? AND unpublish_at <= ?", id, models.Posted, now).
+		Update("status", models.Archived)
+	return result.RowsAffected == 1, result.Error
+}
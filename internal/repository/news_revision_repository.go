@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newsRevisionListColumns leave out the content, which can be large
+const newsRevisionListColumns = "id, news_id, revision, news_title, slug, category, category_id, status, image, editor_id, editor_username, note, created_at"
+
+type NewsRevisionRepository interface {
+	Create(revision *models.NewsRevision) error
+	ListByNews(newsID uint, limit, offset int) ([]models.NewsRevision, int64, error)
+	GetByNumber(newsID uint, revision int) (*models.NewsRevision, error)
+	Exists(newsID uint) (bool, error)
+}
+
+type newsRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsRevisionRepository(db *gorm.DB) NewsRevisionRepository {
+	return &newsRevisionRepository{db: db}
+}
+
+// Create stores the revision with the next number of its news. The news row is locked
+// while numbering, so two editors saving at the same time get consecutive numbers.
+func (r *newsRevisionRepository) Create(revision *models.NewsRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var news models.News
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&news, revision.NewsID).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&models.NewsRevision{}).Where("news_id = ?", revision.NewsID).
+			Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+
+		revision.Revision = last + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// ListByNews returns the revisions of a news item without content, newest first
+func (r *newsRevisionRepository) ListByNews(newsID uint, limit, offset int) ([]models.NewsRevision, int64, error) {
+	var revisions []models.NewsRevision
+	var total int64
+
+	query := r.db.Model(&models.NewsRevision{}).Where("news_id = ?", newsID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Select(newsRevisionListColumns).Order("revision DESC").
+		Offset(offset).Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+func (r *newsRevisionRepository) GetByNumber(newsID uint, revision int) (*models.NewsRevision, error) {
+	var result models.NewsRevision
+	err := r.db.Where("news_id = ? AND revision = ?", newsID, revision).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *newsRevisionRepository) Exists(newsID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.NewsRevision{}).Where("news_id = ?", newsID).Limit(1).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NewsTransitionRepository interface {
+	Create(transition *models.NewsTransition) error
+	ListByNews(newsID uint) ([]models.NewsTransition, error)
+}
+
+type newsTransitionRepository struct {
+	db *gorm.DB
+}
+
+func NewNewsTransitionRepository(db *gorm.DB) NewsTransitionRepository {
+	return &newsTransitionRepository{db: db}
+}
+
+func (r *newsTransitionRepository) Create(transition *models.NewsTransition) error {
+	return r.db.Create(transition).Error
+}
+
+// ListByNews returns the status history of a news item, oldest first
+func (r *newsTransitionRepository) ListByNews(newsID uint) ([]models.NewsTransition, error) {
+	var transitions []models.NewsTransition
+	err := r.db.Where("news_id = ?", newsID).Order("created_at ASC, id ASC").Find(&transitions).Error
+	return transitions, err
+}
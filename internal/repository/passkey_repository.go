@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasskeyRepository interface {
+	Create(passkey *models.Passkey) error
+	GetByCredentialID(credentialID string) (*models.Passkey, error)
+	ListByUser(userID uint) ([]models.Passkey, error)
+	CountByUser(userID uint) (int64, error)
+	UpdateSignCount(id uint, signCount uint32, usedAt time.Time) error
+	Delete(userID, id uint) (bool, error) // Returns false when the passkey does not belong to the user
+}
+
+type passkeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyRepository(db *gorm.DB) PasskeyRepository {
+	return &passkeyRepository{db: db}
+}
+
+func (r *passkeyRepository) Create(passkey *models.Passkey) error {
+	return r.db.Create(passkey).Error
+}
+
+func (r *passkeyRepository) GetByCredentialID(credentialID string) (*models.Passkey, error) {
+	var passkey models.Passkey
+	err := r.db.Where("credential_id = ?", credentialID).First(&passkey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) ListByUser(userID uint) ([]models.Passkey, error) {
+	var passkeys []models.Passkey
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&passkeys).Error
+	return passkeys, err
+}
+
+func (r *passkeyRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Passkey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *passkeyRepository) UpdateSignCount(id uint, signCount uint32, usedAt time.Time) error {
+	return r.db.Model(&models.Passkey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"sign_count": signCount, "last_used_at": usedAt}).Error
+}
+
+func (r *passkeyRepository) Delete(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Passkey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
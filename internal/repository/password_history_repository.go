@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepository interface {
+	Add(userID uint, passwordHash string) error
+	GetRecent(userID uint, limit int) ([]models.PasswordHistory, error)
+	Prune(userID uint, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) Add(userID uint, passwordHash string) error {
+	return r.db.Create(&models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}).Error
+}
+
+func (r *passwordHistoryRepository) GetRecent(userID uint, limit int) ([]models.PasswordHistory, error) {
+	var entries []models.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// Prune keeps only the newest keep entries of the user
+func (r *passwordHistoryRepository) Prune(userID uint, keep int) error {
+	recent, err := r.GetRecent(userID, keep)
+	if err != nil || len(recent) < keep {
+		return err
+	}
+
+	oldest := recent[len(recent)-1]
+	return r.db.Where("user_id = ? AND id < ?", userID, oldest.ID).Delete(&models.PasswordHistory{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	GetByName(name models.UserRole) (*models.Role, error)
+	Create(role *models.Role) error
+	Update(role *models.Role) error
+	Delete(name models.UserRole) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Order("is_built_in DESC, name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) GetByName(name models.UserRole) (*models.Role, error) {
+	var role models.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Save(role).Error
+}
+
+func (r *roleRepository) Delete(name models.UserRole) error {
+	return r.db.Where("name = ?", name).Delete(&models.Role{}).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SecurityEventRepository interface {
+	Create(event *models.SecurityEvent) error
+	ListByUser(userID uint, filter *models.SecurityEventFilter, limit, offset int) ([]models.SecurityEvent, int64, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+func (r *securityEventRepository) Create(event *models.SecurityEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *securityEventRepository) ListByUser(userID uint, filter *models.SecurityEventFilter, limit, offset int) ([]models.SecurityEvent, int64, error) {
+	var events []models.SecurityEvent
+	var total int64
+
+	query := r.db.Model(&models.SecurityEvent{}).Where("user_id = ?", userID)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func (r *securityEventRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.SecurityEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByFamilyID(familyID string) (*models.Session, error)
+	GetActiveByUser(userID uint) ([]models.Session, error)
+	Rotate(id uint, oldJTI, newJTI string, client models.ClientInfo, expiresAt time.Time) (bool, error)
+	Revoke(id uint, reason string) error
+	RevokeByFamilyID(familyID, reason string) error
+	RevokeAllForUser(userID uint, reason string) error
+	RevokeByImpersonator(impersonatorID uint, reason string) (int64, error)
+	CleanupExpired() error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) GetByFamilyID(familyID string) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("family_id = ?", familyID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) GetActiveByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate swaps the current refresh token jti only if oldJTI is still current,
+// so two concurrent refreshes with the same token cannot both succeed.
+func (r *sessionRepository) Rotate(id uint, oldJTI, newJTI string, client models.ClientInfo, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND refresh_jti = ? AND revoked_at IS NULL", id, oldJTI).
+		Updates(map[string]interface{}{
+			"refresh_jti":  newJTI,
+			"last_used_at": time.Now(),
+			"ip_address":   client.IPAddress,
+			"user_agent":   client.UserAgent,
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *sessionRepository) Revoke(id uint, reason string) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+func (r *sessionRepository) RevokeByFamilyID(familyID, reason string) error {
+	return r.db.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+func (r *sessionRepository) RevokeAllForUser(userID uint, reason string) error {
+	return r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+// RevokeByImpersonator ends every open impersonation session started by the super admin
+func (r *sessionRepository) RevokeByImpersonator(impersonatorID uint, reason string) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("impersonator_id = ? AND revoked_at IS NULL", impersonatorID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason})
+	return result.RowsAffected, result.Error
+}
+
+func (r *sessionRepository) CleanupExpired() error {
+	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.Session{}).Error
+}
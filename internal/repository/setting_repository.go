@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettingRepository interface {
+	Get(key string) (string, bool, error)
+	Set(key, value string) error
+	Delete(key string) (bool, error) // Returns false when the key did not exist
+}
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) SettingRepository {
+	return &settingRepository{db: db}
+}
+
+// Get returns the stored value and whether the key exists
+func (r *settingRepository) Get(key string) (string, bool, error) {
+	var setting models.SystemSetting
+	err := r.db.Where("`key` = ?", key).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return setting.Value, true, nil
+}
+
+func (r *settingRepository) Set(key, value string) error {
+	setting := &models.SystemSetting{Key: key, Value: value}
+	return r.db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(setting).Error
+}
+
+func (r *settingRepository) Delete(key string) (bool, error) {
+	result := r.db.Where("`key` = ?", key).Delete(&models.SystemSetting{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
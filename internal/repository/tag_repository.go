@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	List() ([]models.Tag, error)
+	GetByID(id uint) (*models.Tag, error)
+	FindByNameOrSlug(name, slug string) (*models.Tag, error)
+	Taken(name, slug string, exceptID uint) (bool, error)
+	Create(tag *models.Tag) error
+	Update(tag *models.Tag) error
+	Delete(id uint) error
+	CountNews(publishedOnly bool) (map[uint]int64, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) List() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Order("sort_order ASC, name ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByNameOrSlug looks up the slug first, then the name (case-insensitive with the
+// MySQL collation)
+func (r *tagRepository) FindByNameOrSlug(name, slug string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("slug = ?", slug).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.Where("name = ?", name).First(&tag).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Taken reports whether another tag than exceptID already uses the name or the slug
+func (r *tagRepository) Taken(name, slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Tag{}).Where("(name = ? OR slug = ?) AND id <> ?", name, slug, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete removes the tag from every news item and then deletes it
+func (r *tagRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM news_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+// CountNews counts news per tag, only news that is live now when publishedOnly is set
+func (r *tagRepository) CountNews(publishedOnly bool) (map[uint]int64, error) {
+	var rows []struct {
+		TagID uint
+		Count int64
+	}
+
+	query := r.db.Table("news_tags").Select("news_tags.tag_id, COUNT(*) AS count").
+		Joins("JOIN news ON news.id = news_tags.news_id AND news.deleted_at IS NULL").
+		Group("news_tags.tag_id")
+	if publishedOnly {
+		query = query.Scopes(publishedAt(time.Now()))
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
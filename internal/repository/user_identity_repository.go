@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"haslaw-be-services/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	GetBySubject(issuer, subject string) (*models.UserIdentity, error)
+	TouchLastLogin(id uint, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *userIdentityRepository) GetBySubject(issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) TouchLastLogin(id uint, at time.Time) error {
+	return r.db.Model(&models.UserIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
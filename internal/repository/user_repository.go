@@ -2,6 +2,7 @@ package repository
 
 import (
 	"haslaw-be-services/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -11,9 +12,14 @@ type UserRepository interface {
 	GetByID(id uint) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
+	Update(user *models.User, columns ...string) error
 	Delete(id uint) error
-	UpdateRefreshToken(userID uint, refreshToken string) error
+	List(limit, offset int, search string, role models.UserRole) ([]models.User, int64, error)
+	CountActiveByRole(role models.UserRole) (int64, error)
+	CountByRole(role models.UserRole) (int64, error)
+	IncrementTokenVersion(userID uint) error
+	ClaimTOTPStep(userID uint, step int64) (bool, error)
+	UpdateLastLogin(userID uint, at time.Time, ipAddress string) error
 }
 
 type userRepository struct {
@@ -55,14 +61,71 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+// Update writes only the given columns of the user, so saving a stale copy cannot undo
+// a change made in the meantime, such as a deactivation, a new role or a revocation.
+func (r *userRepository) Update(user *models.User, columns ...string) error {
+	return r.db.Model(user).Select(append([]string{"updated_at"}, columns...)).Updates(user).Error
 }
 
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
-func (r *userRepository) UpdateRefreshToken(userID uint, refreshToken string) error {
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("refresh_token", refreshToken).Error
+func (r *userRepository) List(limit, offset int, search string, role models.UserRole) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+
+	if search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("username LIKE ? OR email LIKE ?", pattern, pattern)
+	}
+
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepository) CountActiveByRole(role models.UserRole) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).
+		Where("role = ? AND is_active = ?", role, true).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) CountByRole(role models.UserRole) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) IncrementTokenVersion(userID uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// ClaimTOTPStep records step as the last used TOTP time step only if it is newer than
+// the stored one, so two requests with the same code cannot both pass
+func (r *userRepository) ClaimTOTPStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) UpdateLastLogin(userID uint, at time.Time, ipAddress string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{"last_login_at": at, "last_login_ip": ipAddress}).Error
 }
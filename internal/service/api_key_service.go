@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix marks API keys so they can be told apart from JWTs in the Authorization header
+const APIKeyPrefix = "hsk_"
+
+// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyIPDenied = errors.New("API key is not allowed from this IP address")
+)
+
+// APIKeyService manages API keys and authenticates requests made with them
+type APIKeyService interface {
+	Create(actorID uint, actorRole models.UserRole, request *models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error)
+	List() ([]models.APIKey, error)
+	Revoke(id uint) error
+	Authenticate(key, ip string) (*models.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo  repository.APIKeyRepository
+	userRepo    repository.UserRepository
+	roleService RoleService
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, roleService RoleService) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:  apiKeyRepo,
+		userRepo:    userRepo,
+		roleService: roleService,
+	}
+}
+
+// Create generates a new key. The plaintext key is only part of the returned value.
+func (s *apiKeyService) Create(actorID uint, actorRole models.UserRole, request *models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error) {
+	for _, scope := range request.Scopes {
+		if !scope.IsValid() || scope == models.PermissionAll {
+			return nil, fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	if err := s.roleService.CanGrant(actorRole, request.Scopes); err != nil {
+		return nil, err
+	}
+
+	if err := validateIPList(request.AllowedIPs); err != nil {
+		return nil, err
+	}
+
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	key := APIKeyPrefix + secret
+
+	apiKey := &models.APIKey{
+		Name:        request.Name,
+		Prefix:      key[:len(APIKeyPrefix)+8],
+		KeyHash:     utils.HashToken(key),
+		Scopes:      request.Scopes,
+		AllowedIPs:  request.AllowedIPs,
+		ExpiresAt:   request.ExpiresAt,
+		CreatedByID: actorID,
+	}
+	if err := s.apiKeyRepo.Create(apiKey); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKeyResponse{APIKey: *apiKey, Key: key}, nil
+}
+
+func (s *apiKeyService) List() ([]models.APIKey, error) {
+	return s.apiKeyRepo.List()
+}
+
+func (s *apiKeyService) Revoke(id uint) error {
+	if _, err := s.apiKeyRepo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return s.apiKeyRepo.Revoke(id)
+}
+
+// Authenticate checks the key, its expiry and IP allowlist, and records when it was last used.
+// A key only works while its creator is active and still holds every scope of the key.
+func (s *apiKeyService) Authenticate(key, ip string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByHash(utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if !ipAllowed(apiKey.AllowedIPs, ip) {
+		return nil, ErrAPIKeyIPDenied
+	}
+
+	creator, err := s.userRepo.GetByID(apiKey.CreatedByID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: creator no longer exists", ErrInvalidAPIKey)
+		}
+		return nil, err
+	}
+	if !creator.IsActive {
+		return nil, fmt.Errorf("%w: creator is disabled", ErrInvalidAPIKey)
+	}
+	if err := s.roleService.CanGrant(creator.Role, apiKey.Scopes); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, fmt.Errorf("%w: creator no longer holds every scope", ErrInvalidAPIKey)
+		}
+		return nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval || apiKey.LastUsedIP != ip {
+		if err := s.apiKeyRepo.TouchLastUsed(apiKey.ID, ip, now); err != nil {
+			log.Printf("Warning: Failed to update last used time of API key %d: %v", apiKey.ID, err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+// validateIPList checks that every entry is an IP address or a CIDR range
+func validateIPList(entries []string) error {
+	for _, entry := range entries {
+		if net.ParseIP(entry) == nil {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("invalid IP or CIDR %q", entry)
+			}
+		}
+	}
+	return nil
+}
+
+// ipAllowed reports whether ip matches one of the allowed IPs or CIDR ranges. An empty list allows every IP.
+func ipAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
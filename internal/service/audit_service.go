@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"reflect"
+)
+
+// auditIgnoredFields change on every write and would only add noise to the diff
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// AuditService records administrative mutations and lets super admins query them
+type AuditService interface {
+	Record(entry *models.AuditLog, before, after interface{}) // before/after di-diff per field JSON, nil jika tidak ada
+	Query(filter *models.AuditLogFilter, page, limit int) ([]models.AuditLog, *utils.PaginationMeta, error)
+}
+
+type auditService struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditLogRepo repository.AuditLogRepository) AuditService {
+	return &auditService{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Record stores the entry with the field diff between before and after. A failed
+// write is logged instead of failing the request, the mutation already happened.
+func (s *auditService) Record(entry *models.AuditLog, before, after interface{}) {
+	entry.Changes = diffFields(before, after)
+
+	if err := s.auditLogRepo.Create(entry); err != nil {
+		log.Printf("[TRACE: %s] Warning: Failed to write audit log (%s %s %s): %v",
+			entry.TraceID, entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+func (s *auditService) Query(filter *models.AuditLogFilter, page, limit int) ([]models.AuditLog, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	entries, total, err := s.auditLogRepo.List(filter, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return entries, meta, nil
+}
+
+// diffFields compares the JSON representation of two snapshots, so fields hidden
+// with json:"-" (password hash, TOTP secret) never end up in the audit log
+func diffFields(before, after interface{}) map[string]models.AuditChange {
+	beforeFields := toFieldMap(before)
+	afterFields := toFieldMap(after)
+
+	changes := make(map[string]models.AuditChange)
+	for name, from := range beforeFields {
+		if auditIgnoredFields[name] {
+			continue
+		}
+		to, exists := afterFields[name]
+		if !exists || !reflect.DeepEqual(from, to) {
+			changes[name] = models.AuditChange{From: from, To: to}
+		}
+	}
+	for name, to := range afterFields {
+		if auditIgnoredFields[name] {
+			continue
+		}
+		if _, exists := beforeFields[name]; !exists {
+			changes[name] = models.AuditChange{From: nil, To: to}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func toFieldMap(snapshot interface{}) map[string]interface{} {
+	if snapshot == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/repository"
 	"haslaw-be-services/internal/utils"
@@ -11,113 +12,274 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrSessionNotFound        = errors.New("session not found")
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+)
+
+// SettingRequireTwoFactorSuperAdmin forces every super admin to enroll TOTP
+const SettingRequireTwoFactorSuperAdmin = "security.require_2fa_super_admin"
+
+// twoFactorChallengeTTL is how long a user has to enter the TOTP code after the password step
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// LoginResult is the outcome of a login step. When TwoFactorRequired is set only
+// ChallengeToken is filled and the client must complete the second step.
+type LoginResult struct {
+	User                   *models.User
+	AccessToken            string
+	RefreshToken           string
+	TwoFactorRequired      bool
+	ChallengeToken         string
+	TwoFactorSetupRequired bool
+	PasswordChangeRequired bool
+}
+
 // AuthService interface defines authentication business logic
 type AuthService interface {
-	Login(username, password string) (*models.User, string, string, error) // Returns user, accessToken, refreshToken, error
-	CreateDefaultSuperAdmin() error
-	CreateAdmin(request *models.CreateAdminRequest) (*models.User, error)
+	Login(username, password string, client models.ClientInfo) (*LoginResult, error)
+	CompleteLogin(user *models.User, client models.ClientInfo) (*LoginResult, error) // Issues tokens once every login factor has been verified
+	CreateAdmin(actorID uint, request *models.CreateAdminRequest) (*models.User, error)
 	UpdateProfile(userID uint, request *models.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(userID uint, request *models.ChangePasswordRequest, client models.ClientInfo) (*LoginResult, error) // Revokes every token and starts a fresh session
 	ValidateToken(tokenString string) (*utils.Claims, error)
-	RefreshToken(refreshToken string) (string, string, error) // Returns newAccessToken, newRefreshToken, error
+	RefreshToken(refreshToken string, client models.ClientInfo) (string, string, error) // Returns newAccessToken, newRefreshToken, error
 	Logout(userID uint, token string) error
 	IsTokenBlacklisted(token string) (bool, error)
+	CleanupExpiredTokens() error // Menghapus token blacklist dan sesi yang sudah expire
+	RevokeAllUserTokens(userID uint) error
+	ListSessions(userID uint) ([]models.Session, error)
+	RevokeSession(userID, sessionID uint) error
+	RevokeAllSessions(userID uint) error
 }
 
 // authService implements AuthService interface
 type authService struct {
-	userRepo      repository.UserRepository
-	blacklistRepo repository.BlacklistRepository
+	userRepo    repository.UserRepository
+	revocations TokenRevocationList
+	settingRepo repository.SettingRepository
+	sessionRepo repository.SessionRepository
+	throttler   LoginThrottler
+	passwords   PasswordPolicy
+	roleService RoleService
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, blacklistRepo repository.BlacklistRepository) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	revocations TokenRevocationList,
+	settingRepo repository.SettingRepository,
+	sessionRepo repository.SessionRepository,
+	throttler LoginThrottler,
+	passwords PasswordPolicy,
+	roleService RoleService,
+) AuthService {
 	return &authService{
-		userRepo:      userRepo,
-		blacklistRepo: blacklistRepo,
+		userRepo:    userRepo,
+		revocations: revocations,
+		settingRepo: settingRepo,
+		sessionRepo: sessionRepo,
+		throttler:   throttler,
+		passwords:   passwords,
+		roleService: roleService,
 	}
 }
 
-// Login authenticates user and returns JWT tokens
-func (s *authService) Login(username, password string) (*models.User, string, string, error) {
+// Login authenticates user and returns JWT tokens, or a 2FA challenge when the account has TOTP enabled
+func (s *authService) Login(username, password string, client models.ClientInfo) (*LoginResult, error) {
+	// Tolak lebih awal jika username atau IP sedang di-throttle
+	if err := s.throttler.Check(username, client.IPAddress); err != nil {
+		return nil, err
+	}
+
 	// Get user by username
 	user, err := s.userRepo.GetByUsername(username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, "", "", errors.New("invalid credentials")
+			return nil, s.loginFailed(username, client, "unknown username")
 		}
-		return nil, "", "", err
+		return nil, err
 	}
 
 	// Check password
 	if !utils.CheckPasswordHash(password, user.Password) {
-		return nil, "", "", errors.New("invalid credentials")
+		return nil, s.loginFailed(username, client, "invalid password")
 	}
 
-	// Generate both access and refresh tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Username, user.Role)
-	if err != nil {
-		return nil, "", "", err
+	if !user.IsActive {
+		return nil, errors.New("account is disabled")
 	}
 
-	return user, accessToken, refreshToken, nil
+	if user.HasSecondFactor() {
+		challengeToken, err := utils.GenerateChallengeToken(user.ID, user.Username, utils.PurposeTwoFactorChallenge, twoFactorChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			User:              user,
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	s.throttler.RecordSuccess(username)
+
+	return s.CompleteLogin(user, client)
 }
 
-// CreateDefaultSuperAdmin creates default super admin user if not exists
-func (s *authService) CreateDefaultSuperAdmin() error {
-	_, err := s.userRepo.GetByUsername("superadmin")
-	if err == nil {
-		// Super admin already exists
-		return nil
+// loginFailed records a failed attempt and returns the error reported to the caller
+func (s *authService) loginFailed(username string, client models.ClientInfo, reason string) error {
+	if err := s.throttler.RecordFailure(username, client.IPAddress, reason); err != nil {
+		return err
 	}
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, reason)
+}
 
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+// CompleteLogin starts a new session and issues access and refresh tokens for a fully authenticated user
+func (s *authService) CompleteLogin(user *models.User, client models.ClientInfo) (*LoginResult, error) {
+	opts, err := s.tokenOptionsFor(user)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create default super admin user
-	hashedPassword, err := utils.HashPassword("superadmin123")
+	familyID, err := utils.GenerateSecureToken(24)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	refreshTokenID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
 	}
 
-	superAdmin := &models.User{
-		Username: "superadmin",
-		Email:    "superadmin@haslaw.com",
-		Password: hashedPassword,
-		Role:     models.SuperAdmin,
+	session := &models.Session{
+		FamilyID:   familyID,
+		UserID:     user.ID,
+		RefreshJTI: refreshTokenID,
+		UserAgent:  truncate(client.UserAgent, 512),
+		IPAddress:  client.IPAddress,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
 	}
 
-	if err := s.userRepo.Create(superAdmin); err != nil {
-		return err
+	opts.SessionID = familyID
+	opts.RefreshTokenID = refreshTokenID
+
+	// Generate both access and refresh tokens
+	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Username, user.Role, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Println("Default super admin user created:")
-	log.Println("Username: superadmin")
-	log.Println("Password: superadmin123")
-	log.Println("Please change the password after first login!")
+	return &LoginResult{
+		User:                   user,
+		AccessToken:            accessToken,
+		RefreshToken:           refreshToken,
+		TwoFactorSetupRequired: opts.Restriction == utils.RestrictionTwoFactorSetup,
+		PasswordChangeRequired: opts.Restriction == utils.RestrictionPasswordChange,
+	}, nil
+}
 
-	return nil
+// tokenOptionsFor restricts the tokens of users that still have to change their
+// password, and of super admins that still have to enroll 2FA
+func (s *authService) tokenOptionsFor(user *models.User) (utils.TokenOptions, error) {
+	opts := utils.TokenOptions{TokenVersion: user.TokenVersion}
+
+	if user.MustChangePassword {
+		opts.Restriction = utils.RestrictionPasswordChange
+		return opts, nil
+	}
+
+	if user.Role == models.SuperAdmin && !user.HasSecondFactor() {
+		required, err := s.isTwoFactorRequiredForSuperAdmins()
+		if err != nil {
+			return opts, err
+		}
+		if required {
+			opts.Restriction = utils.RestrictionTwoFactorSetup
+		}
+	}
+
+	return opts, nil
 }
 
-// ValidateToken validates JWT token and returns claims
-func (s *authService) ValidateToken(tokenString string) (*utils.Claims, error) {
-	return utils.ValidateToken(tokenString)
+func (s *authService) isTwoFactorRequiredForSuperAdmins() (bool, error) {
+	value, exists, err := s.settingRepo.Get(SettingRequireTwoFactorSuperAdmin)
+	if err != nil || !exists {
+		return false, err
+	}
+	return value == "true", nil
 }
 
-// RefreshToken generates new access token and refresh token from old refresh token
-func (s *authService) RefreshToken(refreshToken string) (string, string, error) {
-	claims, err := utils.ValidateToken(refreshToken)
+// ValidateToken validates JWT token and returns claims. Tokens of disabled
+// users or tokens issued before a user-wide revocation are rejected.
+func (s *authService) ValidateToken(tokenString string) (*utils.Claims, error) {
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh and challenge tokens cannot be used as access tokens
+	if claims.TokenType != utils.TokenTypeAccess || claims.Purpose != "" {
+		return nil, errors.New("invalid token type")
+	}
+
+	if s.revocations.IsRevoked("", claims.ID) {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// User dan sesi sengaja dibaca dari database (dua query by key per request), supaya
+	// akun yang dinonaktifkan, TokenVersion yang naik, sesi yang dicabut dan perubahan
+	// role langsung berlaku di semua instance tanpa menunggu sync
+	user, err := s.userRepo.GetByID(claims.UserID)
 	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account is disabled")
+	}
+
+	if claims.Version != user.TokenVersion {
+		return nil, errors.New("token has been revoked")
+	}
+
+	session, err := s.getActiveSession(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.IsImpersonation() {
+		if err := s.checkImpersonator(claims, session); err != nil {
+			return nil, err
+		}
+	}
+
+	// Perubahan role langsung berlaku tanpa menunggu token baru
+	claims.Role = string(user.Role)
+
+	return claims, nil
+}
+
+// RefreshToken rotates the refresh token of a session. Presenting a refresh token
+// that has already been rotated is treated as theft and revokes the whole session.
+func (s *authService) RefreshToken(refreshToken string, client models.ClientInfo) (string, string, error) {
+	claims, err := utils.ValidateRefreshToken(refreshToken)
+	if err != nil || claims.TokenType != utils.TokenTypeRefresh || claims.Purpose != "" {
 		return "", "", errors.New("invalid refresh token")
 	}
 
-	// Check if refresh token is blacklisted
-	isBlacklisted, err := s.IsTokenBlacklisted(refreshToken)
+	session, err := s.getActiveSession(claims)
 	if err != nil {
-		return "", "", errors.New("failed to check token status")
+		return "", "", err
 	}
-	if isBlacklisted {
-		return "", "", errors.New("refresh token is blacklisted")
+
+	if claims.ID != session.RefreshJTI {
+		s.revokeForReuse(session)
+		return "", "", errors.New("refresh token reuse detected, session revoked")
 	}
 
 	// Get user to get current role
@@ -126,14 +288,40 @@ func (s *authService) RefreshToken(refreshToken string) (string, string, error)
 		return "", "", errors.New("user not found")
 	}
 
-	// Blacklist the old refresh token
-	expiresAt := time.Now().Add(7 * 24 * time.Hour) // 7 hari
-	if err := s.blacklistRepo.AddToBlacklist(refreshToken, claims.UserID, expiresAt); err != nil {
-		log.Printf("Warning: Failed to blacklist old refresh token: %v", err)
+	if !user.IsActive {
+		return "", "", errors.New("account is disabled")
+	}
+
+	if claims.Version != user.TokenVersion {
+		return "", "", errors.New("refresh token has been revoked")
 	}
 
+	opts, err := s.tokenOptionsFor(user)
+	if err != nil {
+		return "", "", err
+	}
+
+	newRefreshTokenID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	client.UserAgent = truncate(client.UserAgent, 512)
+	rotated, err := s.sessionRepo.Rotate(session.ID, claims.ID, newRefreshTokenID, client, time.Now().Add(utils.RefreshTokenTTL))
+	if err != nil {
+		return "", "", err
+	}
+	if !rotated {
+		// Refresh token yang sama dipakai bersamaan oleh dua request
+		s.revokeForReuse(session)
+		return "", "", errors.New("refresh token reuse detected, session revoked")
+	}
+
+	opts.SessionID = session.FamilyID
+	opts.RefreshTokenID = newRefreshTokenID
+
 	// Generate new tokens (access token: 15 menit, refresh token: 7 hari)
-	newAccessToken, newRefreshToken, err := utils.GenerateTokens(claims.UserID, claims.Username, user.Role)
+	newAccessToken, newRefreshToken, err := utils.GenerateTokens(claims.UserID, claims.Username, user.Role, opts)
 	if err != nil {
 		return "", "", err
 	}
@@ -142,9 +330,22 @@ func (s *authService) RefreshToken(refreshToken string) (string, string, error)
 }
 
 // CreateAdmin creates a new admin user (only super admin can do this)
-func (s *authService) CreateAdmin(request *models.CreateAdminRequest) (*models.User, error) {
+func (s *authService) CreateAdmin(actorID uint, request *models.CreateAdminRequest) (*models.User, error) {
+	// Sama seperti ChangeRole: actor tidak boleh membuat akun dengan permission yang tidak ia miliki
+	actor, err := s.userRepo.GetByID(actorID)
+	if err != nil {
+		return nil, ErrForbidden
+	}
+	permissions, err := s.roleService.PermissionsFor(models.Admin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roleService.CanGrant(actor.Role, permissions); err != nil {
+		return nil, err
+	}
+
 	// Check if username already exists
-	_, err := s.userRepo.GetByUsername(request.Username)
+	_, err = s.userRepo.GetByUsername(request.Username)
 	if err == nil {
 		return nil, errors.New("username already exists")
 	}
@@ -155,21 +356,31 @@ func (s *authService) CreateAdmin(request *models.CreateAdminRequest) (*models.U
 		return nil, errors.New("email already exists")
 	}
 
+	// Password dipilih oleh super admin, jadi harus diganti saat login pertama.
+	// Gunakan undangan agar admin memilih password sendiri.
+	admin := &models.User{
+		Username:           request.Username,
+		Email:              request.Email,
+		Role:               models.Admin,
+		MustChangePassword: true,
+	}
+
+	if err := s.passwords.Validate(request.Password, admin); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(request.Password)
 	if err != nil {
 		return nil, err
 	}
+	admin.Password = hashedPassword
 
-	// Create new admin user
-	admin := &models.User{
-		Username: request.Username,
-		Email:    request.Email,
-		Password: hashedPassword,
-		Role:     models.Admin,
+	if err := s.userRepo.Create(admin); err != nil {
+		return nil, err
 	}
 
-	if err := s.userRepo.Create(admin); err != nil {
+	if err := s.passwords.Remember(admin.ID, hashedPassword); err != nil {
 		return nil, err
 	}
 
@@ -204,44 +415,206 @@ func (s *authService) UpdateProfile(userID uint, request *models.UpdateProfileRe
 	user.Username = request.Username
 	user.Email = request.Email
 
-	// Update password if provided
+	// Update password if provided, the current password has to be confirmed first
 	if request.Password != "" {
+		if !utils.CheckPasswordHash(request.CurrentPassword, user.Password) {
+			return nil, ErrInvalidCurrentPassword
+		}
+
+		if err := s.passwords.Validate(request.Password, user); err != nil {
+			return nil, err
+		}
+
 		hashedPassword, err := utils.HashPassword(request.Password)
 		if err != nil {
 			return nil, err
 		}
 		user.Password = hashedPassword
+		user.MustChangePassword = false
 	}
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(user, "username", "email", "password", "must_change_password"); err != nil {
 		return nil, err
 	}
 
+	// Password baru: semua sesi lain (dan sesi ini) harus login ulang
+	if request.Password != "" {
+		if err := s.passwords.Remember(userID, user.Password); err != nil {
+			return nil, err
+		}
+		if err := s.RevokeAllUserTokens(userID); err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
 
-// Logout invalidates user session by blacklisting the token
+// ChangePassword sets a new password after confirming the current one. Every token
+// of the user is revoked, the caller gets a fresh session without restrictions.
+func (s *authService) ChangePassword(userID uint, request *models.ChangePasswordRequest, client models.ClientInfo) (*LoginResult, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !utils.CheckPasswordHash(request.CurrentPassword, user.Password) {
+		return nil, ErrInvalidCurrentPassword
+	}
+
+	if err := s.passwords.Validate(request.NewPassword, user); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(request.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	user.MustChangePassword = false
+	if err := s.userRepo.Update(user, "password", "must_change_password"); err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Remember(user.ID, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	if err := s.RevokeAllUserTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	// Muat ulang agar token baru memakai token version yang sudah dinaikkan
+	user, err = s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CompleteLogin(user, client)
+}
+
+// Logout blacklists the access token and revokes the session it belongs to
 func (s *authService) Logout(userID uint, token string) error {
 	// Parse token to get expiry time
 	claims, err := utils.ValidateToken(token)
 	if err != nil {
 		// Even if token is invalid, we should still try to blacklist it
 		// Use a default expiry time if we can't parse the token
-		expiresAt := time.Now().Add(15 * time.Minute)
-		return s.blacklistRepo.AddToBlacklist(token, userID, expiresAt)
+		expiresAt := time.Now().Add(utils.AccessTokenTTL)
+		return s.revocations.Revoke(token, "", userID, expiresAt)
 	}
 
 	// Add token to blacklist with its original expiry time
 	expiresAt := time.Unix(claims.ExpiresAt.Unix(), 0)
-	if err := s.blacklistRepo.AddToBlacklist(token, userID, expiresAt); err != nil {
+	if err := s.revocations.Revoke(token, claims.ID, userID, expiresAt); err != nil {
 		return err
 	}
 
-	// Also clear refresh token from user record
-	return s.userRepo.UpdateRefreshToken(userID, "")
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	// Refresh token sesi ini tidak bisa dipakai lagi
+	return s.sessionRepo.RevokeByFamilyID(claims.SessionID, "logout")
 }
 
-// IsTokenBlacklisted checks if a token is blacklisted
+// IsTokenBlacklisted checks if a token is blacklisted. The lookup is served from
+// the in-memory revocation list; ValidateToken still loads the user and session.
 func (s *authService) IsTokenBlacklisted(token string) (bool, error) {
-	return s.blacklistRepo.IsTokenBlacklisted(token)
+	return s.revocations.IsRevoked(utils.HashToken(token), ""), nil
+}
+
+func (s *authService) CleanupExpiredTokens() error {
+	if err := s.revocations.CleanupExpired(); err != nil {
+		return err
+	}
+	return s.sessionRepo.CleanupExpired()
+}
+
+// RevokeAllUserTokens invalidates every access and refresh token issued to the user so far
+func (s *authService) RevokeAllUserTokens(userID uint) error {
+	return s.revokeAll(userID, "revoked")
+}
+
+// ListSessions returns the active sessions of the user
+func (s *authService) ListSessions(userID uint) ([]models.Session, error) {
+	return s.sessionRepo.GetActiveByUser(userID)
+}
+
+// RevokeSession revokes one session owned by the user
+func (s *authService) RevokeSession(userID, sessionID uint) error {
+	sessions, err := s.sessionRepo.GetActiveByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return s.sessionRepo.Revoke(session.ID, "user_revoked")
+		}
+	}
+
+	return ErrSessionNotFound
+}
+
+// RevokeAllSessions revokes every session of the user, including the current one ("log out everywhere")
+func (s *authService) RevokeAllSessions(userID uint) error {
+	return s.revokeAll(userID, "user_revoked")
+}
+
+// revokeAll bumps the token version, which rejects every token already issued to
+// the user, including tokens that do not belong to a session
+func (s *authService) revokeAll(userID uint, reason string) error {
+	if err := s.sessionRepo.RevokeAllForUser(userID, reason); err != nil {
+		return err
+	}
+	return s.userRepo.IncrementTokenVersion(userID)
+}
+
+// getActiveSession loads the session referenced by the sid claim and checks it is still usable
+func (s *authService) getActiveSession(claims *utils.Claims) (*models.Session, error) {
+	if claims.SessionID == "" {
+		return nil, errors.New("token has no session")
+	}
+
+	session, err := s.sessionRepo.GetByFamilyID(claims.SessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.UserID != claims.UserID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, errors.New("session has been revoked or expired")
+	}
+
+	return session, nil
+}
+
+// checkImpersonator ends an impersonation token as soon as its session no longer
+// belongs to the impersonator, or the impersonator lost super admin rights
+func (s *authService) checkImpersonator(claims *utils.Claims, session *models.Session) error {
+	if session.ImpersonatorID == nil || *session.ImpersonatorID != claims.ImpersonatorID {
+		return errors.New("impersonation session mismatch")
+	}
+
+	impersonator, err := s.userRepo.GetByID(claims.ImpersonatorID)
+	if err != nil || !impersonator.IsActive || impersonator.Role != models.SuperAdmin {
+		return errors.New("impersonator is no longer allowed to impersonate")
+	}
+
+	return nil
+}
+
+func (s *authService) revokeForReuse(session *models.Session) {
+	log.Printf("⚠️  Refresh token reuse detected for user %d (session %d), revoking session", session.UserID, session.ID)
+	if err := s.sessionRepo.Revoke(session.ID, "refresh_token_reuse"); err != nil {
+		log.Printf("Warning: Failed to revoke session %d after token reuse: %v", session.ID, err)
+	}
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
 }
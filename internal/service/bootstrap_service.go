@@ -0,0 +1,219 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// SettingSetupTokenHash stores the hash of the one-time setup token while no super admin exists
+const SettingSetupTokenHash = "bootstrap_setup_token_hash"
+
+// Credentials created by earlier versions; accounts still using them are forced to change the password
+const (
+	legacyDefaultUsername = "superadmin"
+	legacyDefaultPassword = "superadmin123"
+)
+
+var (
+	ErrSetupNotAvailable = errors.New("setup has already been completed")
+	ErrInvalidSetupToken = errors.New("invalid setup token")
+)
+
+// BootstrapConfig holds the optional credentials of the first super admin
+type BootstrapConfig struct {
+	AdminUsername string
+	AdminEmail    string
+	AdminPassword string
+}
+
+// BootstrapService creates the first super admin without any hardcoded credentials
+type BootstrapService interface {
+	Bootstrap() error                                         // Dipanggil saat startup
+	Setup(request *models.SetupRequest) (*models.User, error) // Membuat super admin pertama dengan setup token
+}
+
+type bootstrapService struct {
+	userRepo    repository.UserRepository
+	settingRepo repository.SettingRepository
+	authService AuthService
+	passwords   PasswordPolicy
+	config      BootstrapConfig
+}
+
+// NewBootstrapService creates a new bootstrap service
+func NewBootstrapService(
+	userRepo repository.UserRepository,
+	settingRepo repository.SettingRepository,
+	authService AuthService,
+	passwords PasswordPolicy,
+	config BootstrapConfig,
+) BootstrapService {
+	return &bootstrapService{
+		userRepo:    userRepo,
+		settingRepo: settingRepo,
+		authService: authService,
+		passwords:   passwords,
+		config:      config,
+	}
+}
+
+// Bootstrap makes sure a super admin exists or can be created. With BOOTSTRAP_ADMIN_*
+// set the account is created from env and has to change its password at first login,
+// otherwise a one-time setup token is printed to the log for POST /api/v1/setup.
+func (s *bootstrapService) Bootstrap() error {
+	if err := s.flagLegacyDefaultAccount(); err != nil {
+		return err
+	}
+
+	count, err := s.userRepo.CountByRole(models.SuperAdmin)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		_, err := s.settingRepo.Delete(SettingSetupTokenHash)
+		return err
+	}
+
+	if s.config.AdminUsername != "" && s.config.AdminEmail != "" && s.config.AdminPassword != "" {
+		return s.createFromEnv()
+	}
+
+	return s.issueSetupToken()
+}
+
+func (s *bootstrapService) Setup(request *models.SetupRequest) (*models.User, error) {
+	storedHash, exists, err := s.settingRepo.Get(SettingSetupTokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSetupNotAvailable
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(request.Token)), []byte(storedHash)) != 1 {
+		return nil, ErrInvalidSetupToken
+	}
+
+	user := &models.User{
+		Username: request.Username,
+		Email:    request.Email,
+		Role:     models.SuperAdmin,
+	}
+	if err := s.passwords.Validate(request.Password, user); err != nil {
+		return nil, err
+	}
+
+	// Menghapus token lebih dulu mencegah dua request setup berhasil bersamaan
+	claimed, err := s.settingRepo.Delete(SettingSetupTokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrSetupNotAvailable
+	}
+
+	if err := s.createSuperAdmin(user, request.Password); err != nil {
+		// Kembalikan token agar setup bisa diulang tanpa restart
+		if restoreErr := s.settingRepo.Set(SettingSetupTokenHash, storedHash); restoreErr != nil {
+			log.Printf("Warning: Failed to restore setup token, restart the API to get a new one: %v", restoreErr)
+		}
+		return nil, err
+	}
+
+	log.Printf("Super admin '%s' created through the setup endpoint", user.Username)
+	return user, nil
+}
+
+func (s *bootstrapService) createFromEnv() error {
+	user := &models.User{
+		Username:           s.config.AdminUsername,
+		Email:              s.config.AdminEmail,
+		Role:               models.SuperAdmin,
+		MustChangePassword: true,
+	}
+
+	if err := s.passwords.Validate(s.config.AdminPassword, user); err != nil {
+		return err
+	}
+
+	if err := s.createSuperAdmin(user, s.config.AdminPassword); err != nil {
+		return err
+	}
+
+	log.Printf("Super admin '%s' created from BOOTSTRAP_ADMIN_*, the password must be changed at first login", user.Username)
+	return nil
+}
+
+func (s *bootstrapService) createSuperAdmin(user *models.User, password string) error {
+	if _, err := s.userRepo.GetByUsername(user.Username); err == nil {
+		return errors.New("username already exists")
+	}
+	if _, err := s.userRepo.GetByEmail(user.Email); err == nil {
+		return errors.New("email already exists")
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+
+	return s.passwords.Remember(user.ID, hashedPassword)
+}
+
+// issueSetupToken replaces any previous setup token; the token is only ever shown in this log output
+func (s *bootstrapService) issueSetupToken() error {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	if err := s.settingRepo.Set(SettingSetupTokenHash, utils.HashToken(token)); err != nil {
+		return err
+	}
+
+	log.Println("==========================================================")
+	log.Println("No super admin exists yet. Create one with this one-time setup token:")
+	log.Printf("  POST /api/v1/setup {\"token\": \"%s\", \"username\": ..., \"email\": ..., \"password\": ...}", token)
+	log.Println("The token is shown only once and is replaced on every restart until setup is completed.")
+	log.Println("==========================================================")
+
+	return nil
+}
+
+// flagLegacyDefaultAccount forces a password change for the account created with the
+// old hardcoded credentials, and revokes every token issued to it
+func (s *bootstrapService) flagLegacyDefaultAccount() error {
+	user, err := s.userRepo.GetByUsername(legacyDefaultUsername)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.MustChangePassword || !utils.CheckPasswordHash(legacyDefaultPassword, user.Password) {
+		return nil
+	}
+
+	user.MustChangePassword = true
+	if err := s.userRepo.Update(user, "must_change_password"); err != nil {
+		return err
+	}
+	if err := s.authService.RevokeAllUserTokens(user.ID); err != nil {
+		return err
+	}
+
+	log.Printf("Warning: Account '%s' still uses the old default password, it must be changed before any other request is allowed", user.Username)
+	return nil
+}
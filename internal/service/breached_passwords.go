@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// breachedPrefixLength is the SHA-1 prefix length used by the Have I Been Pwned range API
+const breachedPrefixLength = 5
+
+// commonPasswords are always treated as breached, even without a breached password list
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "password", "password1", "password123",
+	"qwerty", "qwerty123", "abc123", "111111", "iloveyou", "admin", "admin123", "administrator",
+	"welcome", "welcome1", "letmein", "monkey", "dragon", "sunshine", "superadmin", "superadmin123",
+	"haslaw", "haslaw123", "Password1!", "P@ssw0rd", "Passw0rd!", "Qwerty123!", "Welcome123!",
+}
+
+// BreachedPasswordSource looks up breached passwords by k-anonymity: only the first
+// five hex characters of the SHA-1 hash are used for the lookup, the caller compares
+// the returned suffixes itself. A remote range API can implement the same interface.
+type BreachedPasswordSource interface {
+	Range(prefix string) ([]string, error)
+}
+
+type localBreachedPasswords struct {
+	suffixes map[string][]string
+}
+
+// NewLocalBreachedPasswordSource loads the built-in common passwords and, when path
+// is set, a file with one upper or lower case SHA-1 hash per line (optionally "HASH:COUNT")
+func NewLocalBreachedPasswordSource(path string) (BreachedPasswordSource, error) {
+	source := &localBreachedPasswords{suffixes: make(map[string][]string)}
+
+	for _, password := range commonPasswords {
+		source.add(sha1Hex(password))
+	}
+
+	if path == "" {
+		return source, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if len(hash) != sha1.Size*2 {
+			continue
+		}
+		source.add(strings.ToUpper(hash))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return source, nil
+}
+
+func (s *localBreachedPasswords) Range(prefix string) ([]string, error) {
+	return s.suffixes[strings.ToUpper(prefix)], nil
+}
+
+func (s *localBreachedPasswords) add(hash string) {
+	prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+	s.suffixes[prefix] = append(s.suffixes[prefix], suffix)
+}
+
+// isBreached hashes the password and checks the suffix against the range of its prefix
+func isBreached(source BreachedPasswordSource, password string) (bool, error) {
+	hash := sha1Hex(password)
+
+	suffixes, err := source.Range(hash[:breachedPrefixLength])
+	if err != nil {
+		return false, err
+	}
+
+	for _, suffix := range suffixes {
+		if strings.EqualFold(suffix, hash[breachedPrefixLength:]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func sha1Hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("a category with this name or slug already exists")
+	ErrCategoryInUse    = errors.New("category still has news, pass move_to to move it to another category")
+	ErrUnknownCategory  = errors.New("unknown category, create it first")
+)
+
+// uncategorized takes news whose old free-text category has no letters or digits
+const (
+	uncategorizedName = "Uncategorized"
+	uncategorizedSlug = "uncategorized"
+)
+
+// CategoryService manages news categories
+type CategoryService interface {
+	List(publishedOnly bool) ([]models.CategoryCount, error) // publishedOnly menghitung berita yang sedang tayang saja
+	Get(id uint) (*models.Category, error)
+	Create(request *models.TaxonomyRequest) (*models.Category, error)
+	Update(id uint, request *models.TaxonomyRequest) (*models.Category, error)
+	Delete(id uint, moveTo *uint) error
+	NormalizeLegacy() (int64, error)
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{categoryRepo: categoryRepo}
+}
+
+func (s *categoryService) List(publishedOnly bool) ([]models.CategoryCount, error) {
+	categories, err := s.categoryRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.categoryRepo.CountNews(publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.CategoryCount, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, models.CategoryCount{Category: category, NewsCount: counts[category.ID]})
+	}
+	return result, nil
+}
+
+func (s *categoryService) Get(id uint) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *categoryService) Create(request *models.TaxonomyRequest) (*models.Category, error) {
+	name, slug, err := taxonomyNameAndSlug(request)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.categoryRepo.Taken(name, slug, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrCategoryExists
+	}
+
+	category := &models.Category{
+		Name:        name,
+		Slug:        slug,
+		Description: request.Description,
+		SortOrder:   request.SortOrder,
+	}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Update replaces the category. A new name is copied onto its news as well.
+func (s *categoryService) Update(id uint, request *models.TaxonomyRequest) (*models.Category, error) {
+	category, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, slug, err := taxonomyNameAndSlug(request)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.categoryRepo.Taken(name, slug, id)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrCategoryExists
+	}
+
+	category.Name = name
+	category.Slug = slug
+	category.Description = request.Description
+	category.SortOrder = request.SortOrder
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// Delete removes an empty category. News in a category that is not empty moves to
+// moveTo first; without moveTo the category is kept.
+func (s *categoryService) Delete(id uint, moveTo *uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	var target *models.Category
+	if moveTo != nil {
+		if *moveTo == id {
+			return errors.New("move_to must be another category")
+		}
+
+		var err error
+		if target, err = s.Get(*moveTo); err != nil {
+			if errors.Is(err, ErrCategoryNotFound) {
+				return errors.New("move_to category not found")
+			}
+			return err
+		}
+	} else {
+		count, err := s.categoryRepo.CountNewsInCategory(id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCategoryInUse
+		}
+	}
+
+	return s.categoryRepo.Delete(id, target)
+}
+
+// NormalizeLegacy links news that only has a free-text category to a category, creating
+// it when needed. Spellings with the same merge key ("Legal Updates", "legal update",
+// "Legal-Updates") end up in one category named after the most used spelling, or in an
+// existing category with that key. Every group is logged so an admin can review the merge.
+// News that already has a category is left alone, so running it again only picks up what is new.
+func (s *categoryService) NormalizeLegacy() (int64, error) {
+	legacy, err := s.categoryRepo.ListLegacy()
+	if err != nil {
+		return 0, err
+	}
+	if len(legacy) == 0 {
+		return 0, nil
+	}
+
+	existing, err := s.categoryRepo.List()
+	if err != nil {
+		return 0, err
+	}
+	byKey := make(map[string]*models.Category, len(existing))
+	for i := range existing {
+		key := categoryMergeKey(existing[i].Name)
+		if byKey[key] == nil {
+			byKey[key] = &existing[i]
+		}
+	}
+
+	type group struct {
+		name  string
+		names []string
+	}
+	groups := make(map[string]*group)
+	var keys []string
+
+	// ListLegacy mengurutkan dari yang paling banyak dipakai, ejaan pertama menjadi nama kategori
+	for _, category := range legacy {
+		name := strings.TrimSpace(category.Name)
+		key := categoryMergeKey(name)
+		if key == "" {
+			name, key = uncategorizedName, uncategorizedSlug
+		}
+
+		if groups[key] == nil {
+			groups[key] = &group{name: name}
+			keys = append(keys, key)
+		}
+		groups[key].names = append(groups[key].names, category.Name)
+	}
+
+	var total int64
+	for _, key := range keys {
+		group := groups[key]
+
+		category := byKey[key]
+		if category == nil {
+			slug := utils.GenerateSlug(group.name)
+			if category, err = s.categoryRepo.FindByNameOrSlug(group.name, slug); errors.Is(err, gorm.ErrRecordNotFound) {
+				category = &models.Category{Name: group.name, Slug: slug}
+				if err = s.categoryRepo.Create(category); err == nil {
+					log.Printf("Category %s created from existing news", category.Name)
+				}
+			}
+			if err != nil {
+				return total, err
+			}
+		}
+
+		count, err := s.categoryRepo.AssignLegacy(category, group.names)
+		if err != nil {
+			return total, err
+		}
+		total += count
+
+		spellings := make([]string, len(group.names))
+		for i, name := range group.names {
+			spellings[i] = fmt.Sprintf("%q", name)
+		}
+		log.Printf("Category %s (%s): merged %s, %d news linked", category.Name, category.Slug, strings.Join(spellings, ", "), count)
+	}
+
+	return total, nil
+}
+
+// categoryMergeKey decides which free-text categories are the same category: the slug
+// with every word folded to its singular, so case, spacing, punctuation and simple
+// plurals do not matter. Only regular English plurals are folded ("updates" -> "update",
+// "policies" -> "policy"); words ending in -ss, -us or -is and words of three letters or
+// fewer are kept, so "business", "status" and "analysis" stay as they are.
+func categoryMergeKey(name string) string {
+	words := strings.Split(utils.GenerateSlug(name), "-")
+	for i, word := range words {
+		switch {
+		case len(word) <= 3 || !strings.HasSuffix(word, "s"):
+		case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		case strings.HasSuffix(word, "ies"):
+			words[i] = strings.TrimSuffix(word, "ies") + "y"
+		default:
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+// taxonomyNameAndSlug trims the name and checks the slug, which defaults to the slug of the name
+func taxonomyNameAndSlug(request *models.TaxonomyRequest) (string, string, error) {
+	name := strings.TrimSpace(request.Name)
+
+	slug := request.Slug
+	if slug == "" {
+		slug = utils.GenerateSlug(name)
+	}
+
+	if name == "" || slug == "" {
+		return "", "", errors.New("name must contain letters or digits")
+	}
+	if !utils.ValidateSlug(slug) {
+		return "", "", errors.New("slug may only contain lowercase letters, digits and dashes")
+	}
+
+	return name, slug, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import "testing"
+
+func TestCategoryMergeKey(t *testing.T) {
+	tests := map[string]string{
+		"Legal Updates":     "legal-update",
+		"legal update":      "legal-update",
+		"Legal-Updates":     "legal-update",
+		" LEGAL  UPDATE ":   "legal-update",
+		"Policies":          "policy",
+		"policy":            "policy",
+		"Corporate Law":     "corporate-law",
+		"Business Analysis": "business-analysis",
+		"Case Status":       "case-status",
+		"Tax & IP":          "tax-ip",
+		"":                  "",
+		"---":               "",
+	}
+
+	for name, want := range tests {
+		if got := categoryMergeKey(name); got != want {
+			t.Errorf("categoryMergeKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"time"
+)
+
+var (
+	ErrImpersonationNotAllowed = errors.New("only an active super admin can impersonate")
+	ErrCannotImpersonate       = errors.New("this user cannot be impersonated")
+	ErrNotImpersonating        = errors.New("no active impersonation session")
+)
+
+// impersonationEndedReason is stored on sessions closed by Stop
+const impersonationEndedReason = "impersonation_ended"
+
+// ImpersonationService lets a super admin act as another admin for a limited time,
+// to reproduce permission or content problems. Impersonation tokens have no refresh
+// token and end with their session.
+type ImpersonationService interface {
+	Start(impersonatorID uint, request *models.StartImpersonationRequest, client models.ClientInfo) (*models.ImpersonationResponse, error)
+	StopSession(impersonatorID uint, familyID string) (*models.Session, error) // Mengakhiri sesi impersonation yang sedang dipakai
+	StopAll(impersonatorID uint) (int64, error)                                // Mengakhiri semua sesi impersonation milik super admin
+}
+
+type impersonationService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	maxTTL      time.Duration
+}
+
+// NewImpersonationService creates a new impersonation service. maxTTL caps the
+// duration a super admin can request.
+func NewImpersonationService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, maxTTL time.Duration) ImpersonationService {
+	return &impersonationService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		maxTTL:      maxTTL,
+	}
+}
+
+func (s *impersonationService) Start(impersonatorID uint, request *models.StartImpersonationRequest, client models.ClientInfo) (*models.ImpersonationResponse, error) {
+	impersonator, err := s.userRepo.GetByID(impersonatorID)
+	if err != nil || !impersonator.IsActive || impersonator.Role != models.SuperAdmin {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	if request.UserID == impersonatorID {
+		return nil, ErrCannotImpersonate
+	}
+
+	user, err := s.userRepo.GetByID(request.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	// Super admin lain tidak boleh dipakai, begitu juga akun yang dinonaktifkan
+	if !user.IsActive || user.Role == models.SuperAdmin {
+		return nil, ErrCannotImpersonate
+	}
+
+	ttl := s.maxTTL
+	if request.DurationMinutes > 0 {
+		if requested := time.Duration(request.DurationMinutes) * time.Minute; requested < ttl {
+			ttl = requested
+		}
+	}
+
+	familyID, err := utils.GenerateSecureToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		FamilyID:       familyID,
+		UserID:         user.ID,
+		UserAgent:      truncate(client.UserAgent, 512),
+		IPAddress:      client.IPAddress,
+		LastUsedAt:     now,
+		ExpiresAt:      now.Add(ttl),
+		ImpersonatorID: &impersonator.ID,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateImpersonationToken(user.ID, user.Username, user.Role,
+		utils.TokenOptions{SessionID: familyID, TokenVersion: user.TokenVersion},
+		utils.ImpersonationOptions{
+			ImpersonatorID:       impersonator.ID,
+			ImpersonatorUsername: impersonator.Username,
+			AllowDestructive:     request.AllowDestructive,
+		},
+		ttl,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &models.ImpersonationResponse{
+		AccessToken:      token,
+		ExpiresAt:        session.ExpiresAt,
+		SessionID:        session.ID,
+		AllowDestructive: request.AllowDestructive,
+		User:             &response,
+	}, nil
+}
+
+func (s *impersonationService) StopSession(impersonatorID uint, familyID string) (*models.Session, error) {
+	session, err := s.sessionRepo.GetByFamilyID(familyID)
+	if err != nil || session.ImpersonatorID == nil || *session.ImpersonatorID != impersonatorID || session.RevokedAt != nil {
+		return nil, ErrNotImpersonating
+	}
+
+	if err := s.sessionRepo.Revoke(session.ID, impersonationEndedReason); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *impersonationService) StopAll(impersonatorID uint) (int64, error) {
+	return s.sessionRepo.RevokeByImpersonator(impersonatorID, impersonationEndedReason)
+}
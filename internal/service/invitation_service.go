@@ -0,0 +1,315 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationExists   = errors.New("an invitation for this email is still open, resend it instead")
+	ErrInvitationClosed   = errors.New("invitation has already been accepted or revoked")
+	ErrInvalidInvitation  = errors.New("invitation is invalid, expired or has been revoked")
+)
+
+// InvitationService invites admins by email. The invitee chooses their own username and password.
+type InvitationService interface {
+	Invite(actorID uint, request *models.CreateInvitationRequest) (*models.Invitation, error)
+	List() ([]models.Invitation, error) // Undangan yang belum diterima atau dicabut, termasuk yang expired
+	Resend(actorID, id uint) (*models.Invitation, error)
+	Revoke(actorID, id uint) (*models.Invitation, error)
+	Accept(request *models.AcceptInvitationRequest) (*models.User, error)
+}
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	roleService    RoleService
+	passwords      PasswordPolicy
+	mailer         utils.Mailer
+	inviteURL      string
+	tokenTTL       time.Duration
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	roleService RoleService,
+	passwords PasswordPolicy,
+	mailer utils.Mailer,
+	inviteURL string,
+	tokenTTL time.Duration,
+) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		roleService:    roleService,
+		passwords:      passwords,
+		mailer:         mailer,
+		inviteURL:      inviteURL,
+		tokenTTL:       tokenTTL,
+	}
+}
+
+func (s *invitationService) Invite(actorID uint, request *models.CreateInvitationRequest) (*models.Invitation, error) {
+	if _, err := s.userRepo.GetByEmail(request.Email); err == nil {
+		return nil, errors.New("email already exists")
+	}
+
+	if _, err := s.invitationRepo.GetOpenByEmail(request.Email); err == nil {
+		return nil, ErrInvitationExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.checkRole(actorID, request.Role); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateInvitationToken(request.Email, s.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.Invitation{
+		Email:       request.Email,
+		Role:        request.Role,
+		TokenHash:   utils.HashToken(token),
+		InvitedByID: actorID,
+		ExpiresAt:   now.Add(s.tokenTTL),
+		SentCount:   1,
+		LastSentAt:  now,
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	s.send(invitation, token)
+
+	invitation.Status = invitation.CurrentStatus(now)
+	return invitation, nil
+}
+
+func (s *invitationService) List() ([]models.Invitation, error) {
+	invitations, err := s.invitationRepo.ListOpen()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range invitations {
+		invitations[i].Status = invitations[i].CurrentStatus(now)
+	}
+
+	return invitations, nil
+}
+
+// Resend issues a new token with a fresh expiry. Links sent earlier stop working.
+func (s *invitationService) Resend(actorID, id uint) (*models.Invitation, error) {
+	invitation, err := s.getOpen(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Role bisa saja sudah dihapus atau actor kehilangan permission sejak undangan dibuat
+	if err := s.checkRole(actorID, invitation.Role); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateInvitationToken(invitation.Email, s.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.TokenHash = utils.HashToken(token)
+	invitation.ExpiresAt = now.Add(s.tokenTTL)
+	invitation.SentCount++
+	invitation.LastSentAt = now
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, err
+	}
+
+	s.send(invitation, token)
+
+	invitation.Status = invitation.CurrentStatus(now)
+	return invitation, nil
+}
+
+// Revoke closes a pending invitation. Like Invite and Resend it needs an actor that may
+// grant the invited role; an invitation to a deleted role can be revoked by anyone.
+func (s *invitationService) Revoke(actorID, id uint) (*models.Invitation, error) {
+	invitation, err := s.getOpen(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRole(actorID, invitation.Role); err != nil && !errors.Is(err, ErrRoleNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.RevokedAt = &now
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, err
+	}
+
+	invitation.Status = invitation.CurrentStatus(now)
+	return invitation, nil
+}
+
+// Accept creates the account of the invitee. The invitation is consumed only after
+// the username and password pass validation, so a rejected password can be retried.
+// An invitation stops working once its inviter is deleted, disabled or can no longer
+// grant the invited role.
+func (s *invitationService) Accept(request *models.AcceptInvitationRequest) (*models.User, error) {
+	if _, err := utils.ValidateInvitationToken(request.Token); err != nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	tokenHash := utils.HashToken(request.Token)
+	invitation, err := s.invitationRepo.GetByTokenHash(tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	if invitation.CurrentStatus(time.Now()) != models.InvitationPending {
+		return nil, ErrInvalidInvitation
+	}
+
+	if err := s.checkRole(invitation.InvitedByID, invitation.Role); err != nil {
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrForbidden) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByUsername(request.Username); err == nil {
+		return nil, errors.New("username already exists")
+	}
+	if _, err := s.userRepo.GetByEmail(invitation.Email); err == nil {
+		return nil, errors.New("email already exists")
+	}
+
+	user := &models.User{
+		Username: request.Username,
+		Email:    invitation.Email,
+		Role:     invitation.Role,
+	}
+	if err := s.passwords.Validate(request.Password, user); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.invitationRepo.MarkAccepted(invitation.ID, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidInvitation
+	}
+
+	hashedPassword, err := utils.HashPassword(request.Password)
+	if err != nil {
+		s.reopen(invitation.ID)
+		return nil, err
+	}
+	user.Password = hashedPassword
+
+	if err := s.userRepo.Create(user); err != nil {
+		s.reopen(invitation.ID)
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	invitation.AcceptedUserID = &user.ID
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		log.Printf("Warning: Failed to link invitation %d to user %d: %v", invitation.ID, user.ID, err)
+	}
+
+	if err := s.passwords.Remember(user.ID, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *invitationService) getOpen(id uint) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return nil, ErrInvitationClosed
+	}
+
+	return invitation, nil
+}
+
+// checkRole makes sure the role exists and the actor is active and may grant every permission of it
+func (s *invitationService) checkRole(actorID uint, name models.UserRole) error {
+	role, err := s.roleService.GetRole(name)
+	if err != nil {
+		return err
+	}
+
+	actor, err := s.userRepo.GetByID(actorID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !actor.IsActive {
+		return ErrForbidden
+	}
+
+	return s.roleService.CanGrant(actor.Role, role.Permissions)
+}
+
+func (s *invitationService) reopen(id uint) {
+	if err := s.invitationRepo.Reopen(id); err != nil {
+		log.Printf("Warning: Failed to reopen invitation %d, resend it to try again: %v", id, err)
+	}
+}
+
+func (s *invitationService) send(invitation *models.Invitation, token string) {
+	if err := s.mailer.Send(invitation.Email, "Undangan admin Haslaw", s.buildEmailBody(invitation, token)); err != nil {
+		log.Printf("Warning: Failed to send invitation %d: %v", invitation.ID, err)
+	}
+}
+
+func (s *invitationService) buildEmailBody(invitation *models.Invitation, token string) string {
+	link := s.inviteURL
+	if parsed, err := url.Parse(s.inviteURL); err == nil {
+		query := parsed.Query()
+		query.Set("token", token)
+		parsed.RawQuery = query.Encode()
+		link = parsed.String()
+	}
+
+	return fmt.Sprintf(`Halo,
+
+Anda diundang sebagai %s di panel admin Haslaw.
+Buka tautan berikut untuk memilih username dan password Anda:
+
+%s
+
+Tautan ini hanya dapat digunakan sekali dan berlaku sampai %s.
+Jika Anda tidak mengenal undangan ini, abaikan email ini.
+`, invitation.Role, link, invitation.ExpiresAt.Format("02 Jan 2006 15:04 MST"))
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"net"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrIPNotAllowed      = errors.New("access from this network is not allowed")
+	ErrCountryNotAllowed = errors.New("access from this country is not allowed")
+	ErrIPAccessLockout   = errors.New("these rules would block your own access to the super admin routes")
+	ErrGeoNotConfigured  = errors.New("country rules need GEO_COUNTRY_HEADER to be configured")
+)
+
+// SettingIPAccessRules stores the IP and country allowlists as JSON. Without it the
+// rules from the environment apply. Delete the row to recover from a lockout.
+const SettingIPAccessRules = "security.ip_access_rules"
+
+// AccessOrigin describes where a request came from
+type AccessOrigin struct {
+	ClientIP string // Client IP resolved through the trusted proxies
+	RemoteIP string // Direct peer, the proxy itself behind nginx
+	Country  string // Country header, only trusted when RemoteIP is a trusted proxy
+}
+
+// IPAccessService restricts the admin route groups to allowed networks and countries.
+// Rules are cached in memory and reloaded by Sync, so changes made on another instance
+// apply without a restart.
+type IPAccessService interface {
+	Check(group models.IPAccessGroup, origin AccessOrigin) error
+	CountryHeader() string
+	GetRules() models.IPAccessRules
+	UpdateRules(rules *models.IPAccessRules, origin AccessOrigin) error
+	ResetRules(origin AccessOrigin) (*models.IPAccessRules, error) // Kembali ke aturan dari env
+	Sync() error
+}
+
+type ipAccessService struct {
+	settingRepo    repository.SettingRepository
+	defaults       models.IPAccessRules
+	trustedProxies []*net.IPNet
+	countryHeader  string
+
+	mutex sync.RWMutex
+	rules models.IPAccessRules
+}
+
+// NewIPAccessService creates the service and loads the stored rules. defaults apply
+// until a super admin saves rules through the API.
+func NewIPAccessService(settingRepo repository.SettingRepository, defaults models.IPAccessRules, trustedProxies []string, countryHeader string) (IPAccessService, error) {
+	s := &ipAccessService{
+		settingRepo:   settingRepo,
+		countryHeader: countryHeader,
+	}
+
+	for _, proxy := range trustedProxies {
+		network, err := parseNetwork(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+		s.trustedProxies = append(s.trustedProxies, network)
+	}
+
+	if err := s.normalize(&defaults); err != nil {
+		return nil, fmt.Errorf("invalid IP access rules in environment: %w", err)
+	}
+	s.defaults = defaults
+
+	if err := s.Sync(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *ipAccessService) Check(group models.IPAccessGroup, origin AccessOrigin) error {
+	s.mutex.RLock()
+	rule := s.rules.For(group)
+	s.mutex.RUnlock()
+
+	return s.checkRule(rule, origin)
+}
+
+func (s *ipAccessService) CountryHeader() string {
+	return s.countryHeader
+}
+
+func (s *ipAccessService) GetRules() models.IPAccessRules {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.rules
+}
+
+// UpdateRules validates and stores the rules. Rules that would reject the caller on
+// the super admin routes are refused, so nobody can lock themselves out by accident.
+func (s *ipAccessService) UpdateRules(rules *models.IPAccessRules, origin AccessOrigin) error {
+	if err := s.normalize(rules); err != nil {
+		return err
+	}
+
+	if err := s.checkRule(rules.SuperAdmin, origin); err != nil {
+		return ErrIPAccessLockout
+	}
+
+	value, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	if err := s.settingRepo.Set(SettingIPAccessRules, string(value)); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.rules = *rules
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func (s *ipAccessService) ResetRules(origin AccessOrigin) (*models.IPAccessRules, error) {
+	if err := s.checkRule(s.defaults.SuperAdmin, origin); err != nil {
+		return nil, ErrIPAccessLockout
+	}
+
+	if _, err := s.settingRepo.Delete(SettingIPAccessRules); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	s.rules = s.defaults
+	s.mutex.Unlock()
+
+	rules := s.defaults
+	return &rules, nil
+}
+
+// Sync reloads the stored rules. Invalid stored rules keep the current rules in place.
+func (s *ipAccessService) Sync() error {
+	value, exists, err := s.settingRepo.Get(SettingIPAccessRules)
+	if err != nil {
+		return err
+	}
+
+	rules := s.defaults
+	if exists {
+		rules = models.IPAccessRules{}
+		if err := json.Unmarshal([]byte(value), &rules); err != nil {
+			return fmt.Errorf("invalid stored IP access rules: %w", err)
+		}
+		if err := s.normalize(&rules); err != nil {
+			return fmt.Errorf("invalid stored IP access rules: %w", err)
+		}
+	}
+
+	s.mutex.Lock()
+	s.rules = rules
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func (s *ipAccessService) checkRule(rule models.IPAccessRule, origin AccessOrigin) error {
+	if !ipAllowed(rule.AllowedIPs, origin.ClientIP) {
+		return ErrIPNotAllowed
+	}
+
+	if len(rule.AllowedCountries) > 0 {
+		country := s.trustedCountry(origin)
+		for _, allowed := range rule.AllowedCountries {
+			if country == allowed {
+				return nil
+			}
+		}
+		return ErrCountryNotAllowed
+	}
+
+	return nil
+}
+
+// trustedCountry returns the country header only when the request was forwarded by a
+// trusted proxy, since clients connecting directly can send any header they like
+func (s *ipAccessService) trustedCountry(origin AccessOrigin) string {
+	if s.countryHeader == "" || origin.Country == "" {
+		return ""
+	}
+
+	remote := net.ParseIP(origin.RemoteIP)
+	if remote == nil {
+		return ""
+	}
+
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(remote) {
+			return strings.ToUpper(strings.TrimSpace(origin.Country))
+		}
+	}
+
+	return ""
+}
+
+// normalize trims the entries, upper-cases country codes and validates both lists
+func (s *ipAccessService) normalize(rules *models.IPAccessRules) error {
+	for _, rule := range []*models.IPAccessRule{&rules.Admin, &rules.SuperAdmin} {
+		ips := make([]string, 0, len(rule.AllowedIPs))
+		for _, ip := range rule.AllowedIPs {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+		if err := validateIPList(ips); err != nil {
+			return err
+		}
+		rule.AllowedIPs = ips
+
+		countries := make([]string, 0, len(rule.AllowedCountries))
+		for _, country := range rule.AllowedCountries {
+			country = strings.ToUpper(strings.TrimSpace(country))
+			if country == "" {
+				continue
+			}
+			if len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+				return fmt.Errorf("invalid country code %q, use ISO 3166-1 alpha-2", country)
+			}
+			countries = append(countries, country)
+		}
+		if len(countries) > 0 && s.countryHeader == "" {
+			return ErrGeoNotConfigured
+		}
+		rule.AllowedCountries = countries
+	}
+
+	return nil
+}
+
+// parseNetwork accepts an IP address or a CIDR range
+func parseNetwork(entry string) (*net.IPNet, error) {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return network, nil
+	}
+
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP or CIDR %q", entry)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lockout scopes
+const (
+	LockoutScopeUsername = "username"
+	LockoutScopeIP       = "ip"
+)
+
+var ErrLockoutNotFound = errors.New("lockout not found")
+
+// LoginThrottledError is returned while an account or IP has to wait before the next login attempt
+type LoginThrottledError struct {
+	Scope      string // username atau ip
+	RetryAfter time.Duration
+	Locked     bool // true untuk lockout sementara, false untuk progressive delay
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, %s locked for %s", e.Scope, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginThrottleConfig controls progressive delays and temporary lockouts
+type LoginThrottleConfig struct {
+	MaxAttempts     int           // Percobaan gagal per username sebelum lockout
+	MaxAttemptsIP   int           // Percobaan gagal per IP sebelum lockout
+	FreeAttempts    int           // Percobaan gagal tanpa delay
+	BaseDelay       time.Duration // Delay pertama, berlipat dua setiap kegagalan berikutnya
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	Window          time.Duration // Kegagalan lebih lama dari ini dilupakan
+}
+
+// LoginLockout describes a username or IP that is currently locked out
+type LoginLockout struct {
+	Scope         string    `json:"scope"`
+	Value         string    `json:"value"`
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+	LastReason    string    `json:"last_reason"`
+	LockedUntil   time.Time `json:"locked_until"`
+}
+
+// LoginThrottler tracks failed login attempts per username and per IP
+type LoginThrottler interface {
+	Check(username, ip string) error
+	RecordFailure(username, ip, reason string) error // Returns *LoginThrottledError when the failure triggers a lockout
+	RecordSuccess(username string)
+	ListLockouts() []LoginLockout
+	ClearLockout(scope, value string) error
+}
+
+type loginAttempts struct {
+	failures      int
+	lastFailureAt time.Time
+	lastReason    string
+	nextAllowedAt time.Time
+	lockedUntil   time.Time
+}
+
+type loginThrottler struct {
+	config      LoginThrottleConfig
+	mutex       sync.Mutex
+	attempts    map[string]*loginAttempts
+	lastCleanup time.Time
+}
+
+// NewLoginThrottler creates an in-memory login throttler
+func NewLoginThrottler(config LoginThrottleConfig) LoginThrottler {
+	return &loginThrottler{
+		config:   config,
+		attempts: make(map[string]*loginAttempts),
+	}
+}
+
+// Check returns a *LoginThrottledError if the username or IP must wait before trying again
+func (t *loginThrottler) Check(username, ip string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	for _, key := range []string{throttleKey(LockoutScopeUsername, username), throttleKey(LockoutScopeIP, ip)} {
+		record := t.get(key, now)
+		if record == nil {
+			continue
+		}
+
+		scope := strings.SplitN(key, ":", 2)[0]
+		if now.Before(record.lockedUntil) {
+			return &LoginThrottledError{Scope: scope, RetryAfter: record.lockedUntil.Sub(now), Locked: true}
+		}
+		if now.Before(record.nextAllowedAt) {
+			return &LoginThrottledError{Scope: scope, RetryAfter: record.nextAllowedAt.Sub(now)}
+		}
+	}
+
+	return nil
+}
+
+func (t *loginThrottler) RecordFailure(username, ip, reason string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	t.cleanup(now)
+
+	var lockout error
+	if err := t.fail(throttleKey(LockoutScopeUsername, username), reason, t.config.MaxAttempts, now); err != nil {
+		lockout = err
+	}
+	if err := t.fail(throttleKey(LockoutScopeIP, ip), reason, t.config.MaxAttemptsIP, now); err != nil && lockout == nil {
+		lockout = err
+	}
+
+	return lockout
+}
+
+// RecordSuccess resets the username counter. The IP counter is left to expire on its own so a
+// single valid account cannot be used to reset attempts made against other accounts.
+func (t *loginThrottler) RecordSuccess(username string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.attempts, throttleKey(LockoutScopeUsername, username))
+}
+
+func (t *loginThrottler) ListLockouts() []LoginLockout {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	lockouts := make([]LoginLockout, 0)
+	for key, record := range t.attempts {
+		if !now.Before(record.lockedUntil) {
+			continue
+		}
+		parts := strings.SplitN(key, ":", 2)
+		lockouts = append(lockouts, LoginLockout{
+			Scope:         parts[0],
+			Value:         parts[1],
+			Failures:      record.failures,
+			LastFailureAt: record.lastFailureAt,
+			LastReason:    record.lastReason,
+			LockedUntil:   record.lockedUntil,
+		})
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].LastFailureAt.After(lockouts[j].LastFailureAt)
+	})
+
+	return lockouts
+}
+
+func (t *loginThrottler) ClearLockout(scope, value string) error {
+	if scope != LockoutScopeUsername && scope != LockoutScopeIP {
+		return errors.New("scope must be username or ip")
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := throttleKey(scope, value)
+	if _, exists := t.attempts[key]; !exists {
+		return ErrLockoutNotFound
+	}
+
+	delete(t.attempts, key)
+	return nil
+}
+
+func (t *loginThrottler) fail(key, reason string, maxAttempts int, now time.Time) error {
+	record := t.get(key, now)
+	if record == nil {
+		record = &loginAttempts{}
+		t.attempts[key] = record
+	}
+
+	record.failures++
+	record.lastFailureAt = now
+	record.lastReason = reason
+
+	if maxAttempts > 0 && record.failures >= maxAttempts {
+		record.lockedUntil = now.Add(t.config.LockoutDuration)
+		return &LoginThrottledError{
+			Scope:      strings.SplitN(key, ":", 2)[0],
+			RetryAfter: t.config.LockoutDuration,
+			Locked:     true,
+		}
+	}
+
+	if extra := record.failures - t.config.FreeAttempts; extra > 0 && t.config.BaseDelay > 0 {
+		delay := t.config.BaseDelay
+		for i := 1; i < extra && delay < t.config.MaxDelay; i++ {
+			delay *= 2
+		}
+		if t.config.MaxDelay > 0 && delay > t.config.MaxDelay {
+			delay = t.config.MaxDelay
+		}
+		record.nextAllowedAt = now.Add(delay)
+	}
+
+	return nil
+}
+
+// get returns the record for key, dropping it first if it has gone stale
+func (t *loginThrottler) get(key string, now time.Time) *loginAttempts {
+	record, exists := t.attempts[key]
+	if !exists {
+		return nil
+	}
+	if t.isStale(record, now) {
+		delete(t.attempts, key)
+		return nil
+	}
+	return record
+}
+
+func (t *loginThrottler) isStale(record *loginAttempts, now time.Time) bool {
+	return !now.Before(record.lockedUntil) && now.Sub(record.lastFailureAt) > t.config.Window
+}
+
+// cleanup removes stale records at most once per minute
+func (t *loginThrottler) cleanup(now time.Time) {
+	if now.Sub(t.lastCleanup) < time.Minute {
+		return
+	}
+	t.lastCleanup = now
+
+	for key, record := range t.attempts {
+		if t.isStale(record, now) {
+			delete(t.attempts, key)
+		}
+	}
+}
+
+func throttleKey(scope, value string) string {
+	if scope == LockoutScopeUsername {
+		value = strings.ToLower(strings.TrimSpace(value))
+	}
+	return scope + ":" + value
+}
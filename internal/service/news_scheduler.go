@@ -0,0 +1,92 @@
+package service
+
+import (
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"log"
+	"strconv"
+	"time"
+)
+
+// newsSchedulerActor is the actor of audit entries written by the scheduler
+const newsSchedulerActor = "system:news_scheduler"
+
+// NewsScheduler publishes scheduled news at publish_at and archives news at unpublish_at.
+// Public queries already respect both times, so the scheduler only has to keep the
+// stored status in line. Running it on several instances at once is safe.
+type NewsScheduler interface {
+	Run() error
+}
+
+type newsScheduler struct {
+	newsRepo       repository.NewsRepository
+	revisionRepo   repository.NewsRevisionRepository
+	transitionRepo repository.NewsTransitionRepository
+	auditService   AuditService
+}
+
+// NewNewsScheduler creates a new news scheduler
+func NewNewsScheduler(
+	newsRepo repository.NewsRepository,
+	revisionRepo repository.NewsRevisionRepository,
+	transitionRepo repository.NewsTransitionRepository,
+	auditService AuditService,
+) NewsScheduler {
+	return &newsScheduler{
+		newsRepo:       newsRepo,
+		revisionRepo:   revisionRepo,
+		transitionRepo: transitionRepo,
+		auditService:   auditService,
+	}
+}
+
+func (s *newsScheduler) Run() error {
+	now := time.Now()
+
+	due, err := s.newsRepo.GetDueForPublish(now)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		s.transition(&due[i], models.Posted, models.AuditActionPublish, revisionNotePublish, s.newsRepo.PublishScheduled, now)
+	}
+
+	expired, err := s.newsRepo.GetDueForArchive(now)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		s.transition(&expired[i], models.Archived, models.AuditActionUpdate, revisionNoteArchive, s.newsRepo.ArchiveExpired, now)
+	}
+
+	return nil
+}
+
+func (s *newsScheduler) transition(news *models.News, status models.NewsStatus, action models.AuditAction, note string, apply func(uint, time.Time) (bool, error), now time.Time) {
+	changed, err := apply(news.ID, now)
+	if err != nil {
+		log.Printf("Warning: Failed to change news %d to %s: %v", news.ID, status, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	after := *news
+	after.Status = status
+	s.auditService.Record(&models.AuditLog{
+		ActorUsername: newsSchedulerActor,
+		Action:        action,
+		EntityType:    "news",
+		EntityID:      strconv.FormatUint(uint64(news.ID), 10),
+	}, news, &after)
+	recordNewsRevision(s.revisionRepo, news, &after, nil, newsSchedulerActor, note)
+	recordNewsTransition(s.transitionRepo, &models.NewsTransition{
+		NewsID:        news.ID,
+		FromStatus:    news.Status,
+		ToStatus:      status,
+		ActorUsername: newsSchedulerActor,
+	})
+
+	log.Printf("News %d (%s) changed to %s by schedule", news.ID, news.Slug, status)
+}
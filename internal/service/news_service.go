@@ -2,80 +2,200 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"haslaw-be-services/internal/models"
 	"haslaw-be-services/internal/repository"
 	"haslaw-be-services/internal/utils"
+	"log"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrNewsNotFound     = errors.New("news not found")
+	ErrUseSchedule      = errors.New("use the schedule endpoint to schedule news")
+	ErrAlreadyScheduled = errors.New("news is already scheduled, reschedule it instead")
+	ErrNotScheduled     = errors.New("news has no schedule")
+	ErrRevisionNotFound = errors.New("news revision not found")
+	ErrSearchQuery      = errors.New("invalid search query")
+)
+
+// Limits of a search query and the length of the snippet around the first match
+const (
+	maxSearchQueryLength = 200
+	searchSnippetWidth   = 200
+)
+
 type NewsService interface {
-	Create(newsData *CreateNewsRequest) (*models.News, error)
-	GetAll(page, limit int, orderBy, category string) ([]models.News, *utils.PaginationMeta, error)
-	GetPublished(page, limit int, orderBy, category string) ([]models.News, *utils.PaginationMeta, error)
+	Create(newsData *CreateNewsRequest, actor NewsActor) (*models.News, error)
+	GetAll(page, limit int, orderBy string, filter *models.NewsFilter) ([]models.News, *utils.PaginationMeta, error)
+	GetPublished(page, limit int, orderBy string, filter *models.NewsFilter) ([]models.News, *utils.PaginationMeta, error)
 	GetDrafts(page, limit int, orderBy string) ([]models.News, *utils.PaginationMeta, error)
+	Search(query string, page, limit int, filter *models.NewsFilter) ([]models.NewsSearchResult, *utils.PaginationMeta, error)          // Semua status, termasuk draft
+	SearchPublished(query string, page, limit int, filter *models.NewsFilter) ([]models.NewsSearchResult, *utils.PaginationMeta, error) // Hanya berita yang sedang tayang
 	GetByID(id uint) (*models.News, error)
 	GetBySlug(slug string) (*models.News, error)
-	Update(id uint, newsData *UpdateNewsRequest) (*models.News, error)
+	GetPublishedByID(id uint) (*models.News, error)       // Hanya berita yang sedang tayang
+	GetPublishedBySlug(slug string) (*models.News, error) // Hanya berita yang sedang tayang
+	Update(id uint, newsData *UpdateNewsRequest, actor NewsActor) (*models.News, error)
 	Delete(id uint) error
-	Publish(id uint) (*models.News, error)
+	Publish(id uint, actor NewsActor) (*models.News, error)
+	Schedule(id uint, request *models.ScheduleNewsRequest, actor NewsActor) (*models.News, error)
+	Reschedule(id uint, request *models.ScheduleNewsRequest, actor NewsActor) (*models.News, error)
+	CancelSchedule(id uint, actor NewsActor) (*models.News, error) // Berita terjadwal kembali ke draft, berita tayang tidak lagi expire
+	ListRevisions(id uint, page, limit int) ([]models.NewsRevision, *utils.PaginationMeta, error)
+	GetRevision(id uint, revision int) (*models.NewsRevision, error)
+	DiffRevisions(id uint, from, to int) (*models.NewsRevisionDiff, error)
+	RestoreRevision(id uint, revision int, actor NewsActor) (*models.News, error) // Isi revisi lama disimpan sebagai revisi baru
+	Transition(id uint, request *models.NewsTransitionRequest, actor NewsActor) (*models.News, error)
+	ListTransitions(id uint) ([]models.NewsTransition, error)
+}
+
+// NewsActor is the user changing a news item, used for ownership and publish checks
+// and as the editor of the revision
+type NewsActor struct {
+	UserID      uint
+	Username    string
+	Permissions []models.Permission
+}
+
+func (a NewsActor) can(permission models.Permission) bool {
+	return models.HasPermission(a.Permissions, permission)
+}
+
+// editorID is the user recorded on revisions and transitions, nil when there is none
+func (a NewsActor) editorID() *uint {
+	if a.UserID == 0 {
+		return nil
+	}
+	id := a.UserID
+	return &id
 }
 
 type CreateNewsRequest struct {
-	NewsTitle string            `json:"news_title" binding:"required"`
-	Category  string            `json:"category" binding:"required"`
-	Status    models.NewsStatus `json:"status" binding:"required"`
-	Content   string            `json:"content" binding:"required"`
-	Image     string            `json:"image" binding:"required"`
+	NewsTitle   string            `json:"news_title" binding:"required"`
+	Category    string            `json:"category" binding:"required"` // Nama atau slug kategori yang sudah ada
+	Status      models.NewsStatus `json:"status" binding:"required"`
+	Content     string            `json:"content" binding:"required"`
+	Image       string            `json:"image" binding:"required"`
+	LegalAdvice bool              `json:"legal_advice"`
+	Tags        []string          `json:"tags"` // Nama atau slug tag yang sudah ada
 }
 
 type UpdateNewsRequest struct {
-	NewsTitle string            `json:"news_title" binding:"required"`
-	Category  string            `json:"category" binding:"required"`
-	Status    models.NewsStatus `json:"status" binding:"required"`
-	Content   string            `json:"content" binding:"required"`
-	Image     string            `json:"image" binding:"required"`
+	NewsTitle   string            `json:"news_title" binding:"required"`
+	Category    string            `json:"category" binding:"required"`
+	Status      models.NewsStatus `json:"status" binding:"required"`
+	Content     string            `json:"content" binding:"required"`
+	Image       string            `json:"image" binding:"required"`
+	LegalAdvice *bool             `json:"legal_advice"` // Kosong = tidak berubah
+	Tags        []string          `json:"tags"`         // Kosong = tidak berubah, [] = hapus semua tag
 }
 
+// Notes stored on revisions, see models.NewsRevision
+const (
+	revisionNoteBaseline       = "baseline"
+	revisionNoteCreate         = "create"
+	revisionNoteUpdate         = "update"
+	revisionNotePublish        = "publish"
+	revisionNoteSchedule       = "schedule"
+	revisionNoteReschedule     = "reschedule"
+	revisionNoteCancelSchedule = "cancel_schedule"
+	revisionNoteArchive        = "archive"
+	revisionNoteTransition     = "transition"
+)
+
 type newsService struct {
-	newsRepo repository.NewsRepository
+	newsRepo        repository.NewsRepository
+	revisionRepo    repository.NewsRevisionRepository
+	transitionRepo  repository.NewsTransitionRepository
+	workflowService NewsWorkflowService
+	categoryRepo    repository.CategoryRepository
+	tagRepo         repository.TagRepository
 }
 
-func NewNewsService(newsRepo repository.NewsRepository) NewsService {
+func NewNewsService(
+	newsRepo repository.NewsRepository,
+	revisionRepo repository.NewsRevisionRepository,
+	transitionRepo repository.NewsTransitionRepository,
+	workflowService NewsWorkflowService,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+) NewsService {
 	return &newsService{
-		newsRepo: newsRepo,
+		newsRepo:        newsRepo,
+		revisionRepo:    revisionRepo,
+		transitionRepo:  transitionRepo,
+		workflowService: workflowService,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
 	}
 }
 
-func (s *newsService) Create(newsData *CreateNewsRequest) (*models.News, error) {
+func (s *newsService) Create(newsData *CreateNewsRequest, actor NewsActor) (*models.News, error) {
 
 	if !newsData.Status.IsValid() {
 		return nil, errors.New("invalid news status")
 	}
 
+	if newsData.Status == models.Scheduled {
+		return nil, ErrUseSchedule
+	}
+
+	category, err := s.resolveCategory(newsData.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.resolveTags(newsData.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	slug := utils.GenerateSlugWithRandomID(newsData.NewsTitle)
 
 	news := &models.News{
-		NewsTitle: newsData.NewsTitle,
-		Slug:      slug,
-		Category:  newsData.Category,
-		Status:    newsData.Status,
-		Content:   newsData.Content,
-		Image:     newsData.Image,
+		NewsTitle:   newsData.NewsTitle,
+		Slug:        slug,
+		Status:      models.Drafted,
+		Content:     newsData.Content,
+		Image:       newsData.Image,
+		AuthorID:    &actor.UserID,
+		LegalAdvice: newsData.LegalAdvice,
+	}
+	setCategory(news, category)
+
+	// Berita baru selalu mulai sebagai draft, status lain harus bisa dicapai langsung dari draft
+	if newsData.Status != models.Drafted {
+		if err := s.applyTransition(news, newsData.Status, "", actor, time.Now()); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.newsRepo.Create(news); err != nil {
 		return nil, err
 	}
 
+	if err := s.newsRepo.ReplaceTags(news, tags); err != nil {
+		return nil, err
+	}
+	news.Tags = tags
+
+	s.recordRevision(nil, news, actor, revisionNoteCreate)
+	if news.Status != models.Drafted {
+		s.recordTransition(news.ID, models.Drafted, news.Status, "", actor)
+	}
+
 	return news, nil
 }
 
-func (s *newsService) GetAll(page, limit int, orderBy, category string) ([]models.News, *utils.PaginationMeta, error) {
+func (s *newsService) GetAll(page, limit int, orderBy string, filter *models.NewsFilter) ([]models.News, *utils.PaginationMeta, error) {
 	offset := (page - 1) * limit
 	orderClause := s.buildOrderClause(orderBy)
 
-	news, total, err := s.newsRepo.GetAll(limit, offset, orderClause)
+	news, total, err := s.newsRepo.GetAll(limit, offset, orderClause, filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,11 +210,11 @@ func (s *newsService) GetAll(page, limit int, orderBy, category string) ([]model
 	return news, meta, nil
 }
 
-func (s *newsService) GetPublished(page, limit int, orderBy, category string) ([]models.News, *utils.PaginationMeta, error) {
+func (s *newsService) GetPublished(page, limit int, orderBy string, filter *models.NewsFilter) ([]models.News, *utils.PaginationMeta, error) {
 	offset := (page - 1) * limit
 	orderClause := s.buildOrderClause(orderBy)
 
-	news, total, err := s.newsRepo.GetPublished(limit, offset, orderClause, category)
+	news, total, err := s.newsRepo.GetPublished(limit, offset, orderClause, filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -128,6 +248,55 @@ func (s *newsService) GetDrafts(page, limit int, orderBy string) ([]models.News,
 	return news, meta, nil
 }
 
+func (s *newsService) Search(query string, page, limit int, filter *models.NewsFilter) ([]models.NewsSearchResult, *utils.PaginationMeta, error) {
+	return s.search(query, page, limit, filter, false)
+}
+
+func (s *newsService) SearchPublished(query string, page, limit int, filter *models.NewsFilter) ([]models.NewsSearchResult, *utils.PaginationMeta, error) {
+	return s.search(query, page, limit, filter, true)
+}
+
+// search ranks news by full-text relevance and highlights the query words in the title
+// and in a snippet of the content
+func (s *newsService) search(query string, page, limit int, filter *models.NewsFilter, publishedOnly bool) ([]models.NewsSearchResult, *utils.PaginationMeta, error) {
+	query = strings.TrimSpace(query)
+	if len(query) > maxSearchQueryLength {
+		return nil, nil, fmt.Errorf("%w: use at most %d characters", ErrSearchQuery, maxSearchQueryLength)
+	}
+
+	terms := utils.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil, fmt.Errorf("%w: q needs a word of at least two letters or digits", ErrSearchQuery)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	results, total, err := s.newsRepo.Search(query, limit, offset, filter, publishedOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range results {
+		results[i].TitleHighlight = utils.Highlight(results[i].NewsTitle, terms)
+		results[i].Snippet = utils.Snippet(results[i].Content, terms, searchSnippetWidth)
+	}
+
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return results, meta, nil
+}
+
 func (s *newsService) GetByID(id uint) (*models.News, error) {
 	return s.newsRepo.GetByID(id)
 }
@@ -136,28 +305,66 @@ func (s *newsService) GetBySlug(slug string) (*models.News, error) {
 	return s.newsRepo.GetBySlug(slug)
 }
 
-func (s *newsService) Update(id uint, newsData *UpdateNewsRequest) (*models.News, error) {
-	news, err := s.newsRepo.GetByID(id)
+func (s *newsService) GetPublishedByID(id uint) (*models.News, error) {
+	return s.newsRepo.GetPublishedByID(id)
+}
+
+func (s *newsService) GetPublishedBySlug(slug string) (*models.News, error) {
+	return s.newsRepo.GetPublishedBySlug(slug)
+}
+
+func (s *newsService) Update(id uint, newsData *UpdateNewsRequest, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("news not found")
-		}
 		return nil, err
 	}
 
+	if err := checkEditable(news, actor); err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	if newsData.LegalAdvice != nil {
+		status := news.Status
+		if newsData.Status != "" {
+			status = newsData.Status
+		}
+		if err := setLegalAdvice(news, *newsData.LegalAdvice, status, actor, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	if newsData.NewsTitle != "" {
 		news.NewsTitle = newsData.NewsTitle
 
 		news.Slug = utils.GenerateSlugWithRandomID(newsData.NewsTitle)
 	}
 	if newsData.Category != "" {
-		news.Category = newsData.Category
+		category, err := s.resolveCategory(newsData.Category)
+		if err != nil {
+			return nil, err
+		}
+		setCategory(news, category)
+	}
+	var tags []models.Tag
+	if newsData.Tags != nil {
+		if tags, err = s.resolveTags(newsData.Tags); err != nil {
+			return nil, err
+		}
 	}
 	if newsData.Status != "" {
 		if !newsData.Status.IsValid() {
 			return nil, errors.New("invalid news status")
 		}
-		news.Status = newsData.Status
+		if newsData.Status == models.Scheduled && news.Status != models.Scheduled {
+			return nil, ErrUseSchedule
+		}
+		// Status lain mengikuti workflow, sama seperti endpoint transition tanpa komentar
+		if newsData.Status != news.Status {
+			if err := s.applyTransition(news, newsData.Status, "", actor, time.Now()); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if newsData.Content != "" {
 		news.Content = newsData.Content
@@ -166,43 +373,586 @@ func (s *newsService) Update(id uint, newsData *UpdateNewsRequest) (*models.News
 		news.Image = newsData.Image
 	}
 
-	if err := s.newsRepo.Update(news); err != nil {
+	if err := s.save(news, &previous, actor, revisionNoteUpdate, ""); err != nil {
 		return nil, err
 	}
 
+	if newsData.Tags != nil {
+		if err := s.newsRepo.ReplaceTags(news, tags); err != nil {
+			return nil, err
+		}
+		news.Tags = tags
+	}
+
 	return news, nil
 }
 
 func (s *newsService) Delete(id uint) error {
-	_, err := s.newsRepo.GetByID(id)
+	if _, err := s.getNews(id); err != nil {
+		return err
+	}
+
+	return s.newsRepo.Delete(id)
+}
+
+func (s *newsService) Publish(id uint, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	// Berita terjadwal yang dipublish langsung mendapat waktu tayang sekarang
+	if err := s.applyTransition(news, models.Posted, "", actor, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(news, &previous, actor, revisionNotePublish, ""); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
+
+// Schedule publishes a draft at publish_at, or sets the expiry of news that is already live.
+// Either way the news is archived at unpublish_at when it is set.
+func (s *newsService) Schedule(id uint, request *models.ScheduleNewsRequest, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	now := time.Now()
+	switch {
+	case news.Status == models.Scheduled, news.Status == models.Posted && news.UnpublishAt != nil:
+		return nil, ErrAlreadyScheduled
+	case news.Status == models.Posted:
+		if request.UnpublishAt == nil {
+			return nil, errors.New("unpublish_at is required for published news")
+		}
+	default:
+		if request.PublishAt == nil {
+			return nil, errors.New("publish_at is required")
+		}
+		if err := s.applyTransition(news, models.Scheduled, "", actor, now); err != nil {
+			return nil, err
+		}
+		news.PublishAt = request.PublishAt
+	}
+
+	return s.saveSchedule(news, &previous, request.UnpublishAt, now, actor, revisionNoteSchedule)
+}
+
+// Reschedule moves the publish time of scheduled news and/or the expiry. Omitted times are kept.
+func (s *newsService) Reschedule(id uint, request *models.ScheduleNewsRequest, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	unpublishAt := news.UnpublishAt
+	if request.UnpublishAt != nil {
+		unpublishAt = request.UnpublishAt
+	}
+
+	switch {
+	case news.Status == models.Scheduled:
+		if request.PublishAt != nil {
+			news.PublishAt = request.PublishAt
+		}
+	case news.Status == models.Posted && news.UnpublishAt != nil:
+		if request.UnpublishAt == nil {
+			return nil, errors.New("unpublish_at is required for published news")
+		}
+	default:
+		return nil, ErrNotScheduled
+	}
+
+	return s.saveSchedule(news, &previous, unpublishAt, time.Now(), actor, revisionNoteReschedule)
+}
+
+func (s *newsService) CancelSchedule(id uint, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	switch {
+	case news.Status == models.Scheduled:
+		// Berita tetap disetujui, cukup dijadwalkan atau dipublish ulang
+		if err := s.applyTransition(news, models.Approved, "", actor, time.Now()); err != nil {
+			return nil, err
+		}
+		news.UnpublishAt = nil
+	case news.Status == models.Posted && news.UnpublishAt != nil:
+		news.UnpublishAt = nil
+	default:
+		return nil, ErrNotScheduled
+	}
+
+	if err := s.save(news, &previous, actor, revisionNoteCancelSchedule, ""); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
+
+// saveSchedule checks that the publish time is still ahead and the expiry comes after it
+func (s *newsService) saveSchedule(news, previous *models.News, unpublishAt *time.Time, now time.Time, actor NewsActor, note string) (*models.News, error) {
+	if news.Status == models.Scheduled && !news.PublishAt.After(now) {
+		return nil, errors.New("publish_at must be in the future")
+	}
+
+	if unpublishAt != nil {
+		start := now
+		if news.Status == models.Scheduled {
+			start = *news.PublishAt
+		}
+		if !unpublishAt.After(start) {
+			return nil, errors.New("unpublish_at must be after publish_at and in the future")
+		}
+	}
+	news.UnpublishAt = unpublishAt
+
+	if err := s.save(news, previous, actor, note, ""); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
+
+func (s *newsService) ListRevisions(id uint, page, limit int) ([]models.NewsRevision, *utils.PaginationMeta, error) {
+	if _, err := s.getNews(id); err != nil {
+		return nil, nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	revisions, total, err := s.revisionRepo.ListByNews(id, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return revisions, meta, nil
+}
+
+func (s *newsService) GetRevision(id uint, revision int) (*models.NewsRevision, error) {
+	if _, err := s.getNews(id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.revisionRepo.GetByNumber(id, revision)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("news not found")
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// DiffRevisions compares two revisions in either order: the changed fields with their
+// old and new value, and the content line by line
+func (s *newsService) DiffRevisions(id uint, from, to int) (*models.NewsRevisionDiff, error) {
+	fromRevision, err := s.GetRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.GetRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.NewsRevisionDiff{
+		NewsID:         id,
+		Fields:         []models.NewsFieldChange{},
+		ContentChanged: fromRevision.Content != toRevision.Content,
+		Content:        utils.DiffLines(fromRevision.Content, toRevision.Content),
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"news_title", fromRevision.NewsTitle, toRevision.NewsTitle},
+		{"slug", fromRevision.Slug, toRevision.Slug},
+		{"category", fromRevision.Category, toRevision.Category},
+		{"status", string(fromRevision.Status), string(toRevision.Status)},
+		{"image", fromRevision.Image, toRevision.Image},
+	}
+	for _, field := range fields {
+		if field.from != field.to {
+			diff.Fields = append(diff.Fields, models.NewsFieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+
+	// Isi lengkap sudah ada di Content, metadata revisi cukup tanpa content
+	fromRevision.Content, toRevision.Content = "", ""
+	diff.From, diff.To = fromRevision, toRevision
+
+	return diff, nil
+}
+
+// RestoreRevision copies title, category, content and image of an old revision onto the
+// news and saves it as a new revision. Status and schedule stay as they are, use publish
+// and schedule to change those.
+func (s *newsService) RestoreRevision(id uint, revision int, actor NewsActor) (*models.News, error) {
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEditable(news, actor); err != nil {
+		return nil, err
+	}
+
+	source, err := s.GetRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	if source.NewsTitle != news.NewsTitle {
+		news.NewsTitle = source.NewsTitle
+		news.Slug = utils.GenerateSlugWithRandomID(source.NewsTitle)
+	}
+	// Kategori yang sudah dihapus tidak dikembalikan, berita tetap di kategori sekarang
+	if source.CategoryID != nil {
+		if category, err := s.categoryRepo.GetByID(*source.CategoryID); err == nil {
+			setCategory(news, category)
+		}
+	}
+	news.Content = source.Content
+	news.Image = source.Image
+
+	if err := s.save(news, &previous, actor, fmt.Sprintf("restore of revision %d", revision), ""); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
+
+// Transition moves the news along the editorial workflow. Scheduling needs a publish
+// time, so it goes through Schedule instead.
+func (s *newsService) Transition(id uint, request *models.NewsTransitionRequest, actor NewsActor) (*models.News, error) {
+	if request.Status == models.Scheduled {
+		return nil, ErrUseSchedule
+	}
+
+	news, err := s.getNews(id)
+	if err != nil {
+		return nil, err
+	}
+	previous := *news
+
+	if request.LegalAdvice != nil {
+		if err := setLegalAdvice(news, *request.LegalAdvice, request.Status, actor, time.Now()); err != nil {
+			return nil, err
 		}
+	}
+
+	if err := s.applyTransition(news, request.Status, request.Comment, actor, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(news, &previous, actor, revisionNoteTransition, strings.TrimSpace(request.Comment)); err != nil {
+		return nil, err
+	}
+
+	return news, nil
+}
+
+func (s *newsService) ListTransitions(id uint) ([]models.NewsTransition, error) {
+	if _, err := s.getNews(id); err != nil {
+		return nil, err
+	}
+
+	return s.transitionRepo.ListByNews(id)
+}
+
+// applyTransition checks the workflow rule from the current status to status and
+// changes the status. Legal advice can only move towards publication once a partner
+// approved it; moving back to draft or review drops that approval.
+func (s *newsService) applyTransition(news *models.News, status models.NewsStatus, comment string, actor NewsActor, now time.Time) error {
+	if !status.IsValid() {
+		return errors.New("invalid news status")
+	}
+
+	rule, ok := s.workflowService.Rule(news.Status, status)
+	if !ok {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, news.Status, status)
+	}
+
+	if !canTransition(news, rule, actor) {
+		return ErrForbidden
+	}
+
+	if rule.RequireComment && strings.TrimSpace(comment) == "" {
+		return ErrCommentRequired
+	}
+
+	switch status {
+	case models.Drafted, models.InReview, models.ChangesRequested:
+		news.LegalApprovedBy = nil
+		news.LegalApprovedAt = nil
+	case models.Approved, models.Scheduled, models.Posted:
+		if news.LegalAdvice && news.LegalApprovedBy == nil {
+			if !actor.can(models.PermissionNewsLegal) {
+				return ErrLegalApprovalRequired
+			}
+			approvedBy := actor.UserID
+			news.LegalApprovedBy = &approvedBy
+			news.LegalApprovedAt = &now
+		}
+	}
+
+	setStatus(news, status, now)
+	return nil
+}
+
+// save updates the news and records the new revision, plus a transition when the
+// status changed
+func (s *newsService) save(news, previous *models.News, actor NewsActor, note, comment string) error {
+	if err := s.newsRepo.Update(news); err != nil {
 		return err
 	}
 
-	return s.newsRepo.Delete(id)
+	s.recordRevision(previous, news, actor, note)
+	if previous.Status != news.Status {
+		s.recordTransition(news.ID, previous.Status, news.Status, comment, actor)
+	}
+	return nil
 }
 
-func (s *newsService) Publish(id uint) (*models.News, error) {
-	news, err := s.newsRepo.GetByID(id)
+func (s *newsService) recordRevision(previous, news *models.News, actor NewsActor, note string) {
+	recordNewsRevision(s.revisionRepo, previous, news, actor.editorID(), actor.Username, note)
+}
+
+func (s *newsService) recordTransition(newsID uint, from, to models.NewsStatus, comment string, actor NewsActor) {
+	recordNewsTransition(s.transitionRepo, &models.NewsTransition{
+		NewsID:        newsID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Comment:       comment,
+		ActorID:       actor.editorID(),
+		ActorUsername: actor.Username,
+	})
+}
+
+// recordNewsTransition stores one step of the status history. Like revisions, a failure
+// is only logged because the change itself is already saved.
+func recordNewsTransition(transitionRepo repository.NewsTransitionRepository, transition *models.NewsTransition) {
+	if err := transitionRepo.Create(transition); err != nil {
+		log.Printf("Warning: Failed to record status change of news %d: %v", transition.NewsID, err)
+	}
+}
+
+// recordNewsRevision stores news as a new revision. News created before revisions were
+// kept has none yet, so its state before the change is stored first as a baseline.
+// The change itself is already saved, a failure here is only logged.
+func recordNewsRevision(revisionRepo repository.NewsRevisionRepository, previous, news *models.News, editorID *uint, editorUsername, note string) {
+	if previous != nil {
+		exists, err := revisionRepo.Exists(news.ID)
+		if err != nil {
+			log.Printf("Warning: Failed to check revisions of news %d: %v", news.ID, err)
+			return
+		}
+		if !exists {
+			if err := revisionRepo.Create(newsRevision(previous, nil, "", revisionNoteBaseline)); err != nil {
+				log.Printf("Warning: Failed to record baseline revision of news %d: %v", news.ID, err)
+			}
+		}
+	}
+
+	if err := revisionRepo.Create(newsRevision(news, editorID, editorUsername, note)); err != nil {
+		log.Printf("Warning: Failed to record revision of news %d: %v", news.ID, err)
+	}
+}
+
+func newsRevision(news *models.News, editorID *uint, editorUsername, note string) *models.NewsRevision {
+	return &models.NewsRevision{
+		NewsID:         news.ID,
+		NewsTitle:      news.NewsTitle,
+		Slug:           news.Slug,
+		Category:       news.Category,
+		CategoryID:     news.CategoryID,
+		Status:         news.Status,
+		Content:        news.Content,
+		Image:          news.Image,
+		EditorID:       editorID,
+		EditorUsername: editorUsername,
+		Note:           note,
+	}
+}
+
+// resolveCategory finds an existing category by name or slug
+func (s *newsService) resolveCategory(value string) (*models.Category, error) {
+	value = strings.TrimSpace(value)
+	category, err := s.categoryRepo.FindByNameOrSlug(value, utils.GenerateSlug(value))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("news not found")
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCategory, value)
 		}
 		return nil, err
 	}
+	return category, nil
+}
 
-	if news.Status != models.Drafted {
-		return nil, errors.New("only draft news can be published")
+// resolveTags finds existing tags by name or slug, ignoring blanks and duplicates
+func (s *newsService) resolveTags(values []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(values))
+	seen := make(map[uint]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		tag, err := s.tagRepo.FindByNameOrSlug(value, utils.GenerateSlug(value))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownTag, value)
+			}
+			return nil, err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags = append(tags, *tag)
+		}
 	}
+	return tags, nil
+}
 
-	if err := s.newsRepo.Publish(id); err != nil {
+// setCategory links the news to the category and keeps the name in news.category
+func setCategory(news *models.News, category *models.Category) {
+	news.Category = category.Name
+	news.CategoryID = &category.ID
+	news.CategoryDetail = category
+}
+
+// checkEditable allows editing unless the news is waiting for or passed review. Live
+// legal advice can only be changed by a partner.
+func checkEditable(news *models.News, actor NewsActor) error {
+	if !canEdit(news, actor) {
+		return ErrForbidden
+	}
+
+	if news.Status == models.InReview || news.Status == models.Approved {
+		return ErrNewsLocked
+	}
+
+	if news.LegalAdvice && (news.Status == models.Posted || news.Status == models.Scheduled) && !actor.can(models.PermissionNewsLegal) {
+		return ErrLegalApprovalRequired
+	}
+
+	return nil
+}
+
+// setLegalAdvice flags or unflags the news as legal advice, status being the status the
+// news ends up in. Only a partner may remove the flag; a new flag needs a fresh approval.
+// News that is or stays live or scheduled never waits for that approval, so only a
+// partner may flag it and the flag counts as their approval.
+func setLegalAdvice(news *models.News, legalAdvice bool, status models.NewsStatus, actor NewsActor, now time.Time) error {
+	if news.LegalAdvice == legalAdvice {
+		return nil
+	}
+
+	if !legalAdvice && !actor.can(models.PermissionNewsLegal) {
+		return ErrLegalApprovalRequired
+	}
+
+	if legalAdvice {
+		news.LegalApprovedBy = nil
+		news.LegalApprovedAt = nil
+		if status == models.Posted || status == models.Scheduled {
+			if !actor.can(models.PermissionNewsLegal) {
+				return ErrLegalApprovalRequired
+			}
+			approvedBy := actor.UserID
+			news.LegalApprovedBy = &approvedBy
+			news.LegalApprovedAt = &now
+		}
+	}
+	news.LegalAdvice = legalAdvice
+	return nil
+}
+
+// canTransition reports whether the actor holds one of the permissions of the rule.
+// news:edit_own only counts for the author, edit_any covers it for everyone else.
+func canTransition(news *models.News, rule models.NewsTransitionRule, actor NewsActor) bool {
+	isOwner := news.AuthorID != nil && *news.AuthorID == actor.UserID
+	for _, permission := range rule.Permissions {
+		if permission == models.PermissionNewsEditOwn {
+			if (isOwner && actor.can(permission)) || actor.can(models.PermissionNewsEditAny) {
+				return true
+			}
+			continue
+		}
+		if actor.can(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// canEdit checks edit_any, or edit_own for the author. News that is live or scheduled
+// also needs the publish permission.
+func canEdit(news *models.News, actor NewsActor) bool {
+	isOwner := news.AuthorID != nil && *news.AuthorID == actor.UserID
+	if !actor.can(models.PermissionNewsEditAny) && !(isOwner && actor.can(models.PermissionNewsEditOwn)) {
+		return false
+	}
+
+	if (news.Status == models.Posted || news.Status == models.Scheduled) && !actor.can(models.PermissionNewsPublish) {
+		return false
+	}
+
+	return true
+}
+
+func (s *newsService) getNews(id uint) (*models.News, error) {
+	news, err := s.newsRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNewsNotFound
+		}
 		return nil, err
 	}
+	return news, nil
+}
 
-	return s.newsRepo.GetByID(id)
+// setStatus changes the status and keeps the schedule consistent: news that goes live
+// by hand gets the current publish time and loses an expiry that already passed,
+// news taken off the schedule loses its publish time
+func setStatus(news *models.News, status models.NewsStatus, now time.Time) {
+	switch {
+	case status == models.Posted:
+		if news.PublishAt == nil || news.PublishAt.After(now) {
+			news.PublishAt = &now
+		}
+		if news.UnpublishAt != nil && !news.UnpublishAt.After(now) {
+			news.UnpublishAt = nil
+		}
+	case news.Status == models.Scheduled:
+		news.PublishAt = nil
+	}
+	news.Status = status
 }
 
 func (s *newsService) buildOrderClause(orderBy string) string {
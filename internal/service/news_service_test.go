@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"testing"
+)
+
+// fakeNewsRepo holds a single news item
+type fakeNewsRepo struct {
+	repository.NewsRepository
+	news    *models.News
+	updated bool
+}
+
+func (r *fakeNewsRepo) GetByID(id uint) (*models.News, error) {
+	news := *r.news
+	return &news, nil
+}
+
+func (r *fakeNewsRepo) Update(news *models.News) error {
+	r.updated = true
+	return nil
+}
+
+// fakeNewsRevisionRepo drops every revision
+type fakeNewsRevisionRepo struct {
+	repository.NewsRevisionRepository
+}
+
+func (fakeNewsRevisionRepo) Exists(newsID uint) (bool, error) { return true, nil }
+
+func (fakeNewsRevisionRepo) Create(revision *models.NewsRevision) error { return nil }
+
+func TestUpdateLegalAdviceOnLiveNews(t *testing.T) {
+	const authorID, partnerID = 7, 9
+	editor := []models.Permission{models.PermissionNewsEditAny, models.PermissionNewsPublish}
+	partner := append([]models.Permission{models.PermissionNewsLegal}, editor...)
+
+	tests := []struct {
+		name        string
+		status      models.NewsStatus
+		actor       NewsActor
+		wantErr     error
+		wantApprove bool
+	}{
+		{"editor flags posted news", models.Posted, NewsActor{UserID: authorID, Permissions: editor}, ErrLegalApprovalRequired, false},
+		{"editor flags scheduled news", models.Scheduled, NewsActor{UserID: authorID, Permissions: editor}, ErrLegalApprovalRequired, false},
+		{"partner flags posted news", models.Posted, NewsActor{UserID: partnerID, Permissions: partner}, nil, true},
+		{"partner flags scheduled news", models.Scheduled, NewsActor{UserID: partnerID, Permissions: partner}, nil, true},
+		{"editor flags a draft", models.Drafted, NewsActor{UserID: authorID, Permissions: editor}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author := uint(authorID)
+			newsRepo := &fakeNewsRepo{news: &models.News{ID: 1, AuthorID: &author, Status: tt.status}}
+			service := NewNewsService(newsRepo, fakeNewsRevisionRepo{}, nil, nil, nil, nil)
+
+			legalAdvice := true
+			news, err := service.Update(1, &UpdateNewsRequest{LegalAdvice: &legalAdvice}, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if newsRepo.updated {
+					t.Fatal("rejected change was saved")
+				}
+				return
+			}
+
+			if !news.LegalAdvice {
+				t.Fatal("news was not flagged as legal advice")
+			}
+			approved := news.LegalApprovedBy != nil && *news.LegalApprovedBy == tt.actor.UserID && news.LegalApprovedAt != nil
+			if approved != tt.wantApprove {
+				t.Fatalf("approved = %v, want %v", approved, tt.wantApprove)
+			}
+			if news.Status != tt.status {
+				t.Fatalf("status = %s, want %s", news.Status, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"sync"
+)
+
+var (
+	ErrInvalidTransition     = errors.New("this status change is not part of the editorial workflow")
+	ErrCommentRequired       = errors.New("a comment is required for this status change")
+	ErrLegalApprovalRequired = errors.New("news containing legal advice must be approved by a partner (news:approve_legal)")
+	ErrNewsLocked            = errors.New("news in review or approved cannot be edited, request changes first")
+)
+
+// SettingNewsWorkflow stores the editorial workflow as JSON. Without it DefaultNewsWorkflow applies.
+const SettingNewsWorkflow = "news.workflow"
+
+// DefaultNewsWorkflow is Draft -> In Review -> Changes Requested / Approved -> Posted -> Archived.
+// Authors submit their own news, reviewers approve or send it back with a comment and
+// publishers put approved news live or on the schedule.
+func DefaultNewsWorkflow() models.NewsWorkflow {
+	authors := []models.Permission{models.PermissionNewsEditOwn, models.PermissionNewsEditAny}
+	reviewers := []models.Permission{models.PermissionNewsReview}
+	publishers := []models.Permission{models.PermissionNewsPublish}
+
+	return models.NewsWorkflow{Transitions: []models.NewsTransitionRule{
+		{From: models.Drafted, To: models.InReview, Permissions: authors},
+		{From: models.ChangesRequested, To: models.InReview, Permissions: authors},
+		{From: models.InReview, To: models.Drafted, Permissions: authors}, // Ditarik kembali oleh penulis
+		{From: models.InReview, To: models.ChangesRequested, Permissions: reviewers, RequireComment: true},
+		{From: models.InReview, To: models.Approved, Permissions: reviewers},
+		{From: models.Approved, To: models.ChangesRequested, Permissions: reviewers, RequireComment: true},
+		{From: models.Approved, To: models.Posted, Permissions: publishers},
+		{From: models.Approved, To: models.Scheduled, Permissions: publishers},
+		{From: models.Scheduled, To: models.Posted, Permissions: publishers},
+		{From: models.Scheduled, To: models.Approved, Permissions: publishers}, // Jadwal dibatalkan
+		{From: models.Posted, To: models.Archived, Permissions: publishers},
+		{From: models.Archived, To: models.Drafted, Permissions: []models.Permission{models.PermissionNewsEditAny}},
+	}}
+}
+
+// NewsWorkflowService holds the editorial workflow. It is cached in memory and reloaded
+// by Sync, so changes made on another instance apply without a restart.
+type NewsWorkflowService interface {
+	GetWorkflow() models.NewsWorkflow
+	Rule(from, to models.NewsStatus) (models.NewsTransitionRule, bool)
+	UpdateWorkflow(workflow *models.NewsWorkflow) error
+	ResetWorkflow() (*models.NewsWorkflow, error) // Kembali ke DefaultNewsWorkflow
+	Sync() error
+}
+
+type newsWorkflowService struct {
+	settingRepo repository.SettingRepository
+
+	mutex    sync.RWMutex
+	workflow models.NewsWorkflow
+}
+
+// NewNewsWorkflowService creates the service and loads the stored workflow
+func NewNewsWorkflowService(settingRepo repository.SettingRepository) (NewsWorkflowService, error) {
+	s := &newsWorkflowService{settingRepo: settingRepo}
+
+	if err := s.Sync(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *newsWorkflowService) GetWorkflow() models.NewsWorkflow {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.workflow
+}
+
+func (s *newsWorkflowService) Rule(from, to models.NewsStatus) (models.NewsTransitionRule, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, rule := range s.workflow.Transitions {
+		if rule.From == from && rule.To == to {
+			return rule, true
+		}
+	}
+	return models.NewsTransitionRule{}, false
+}
+
+func (s *newsWorkflowService) UpdateWorkflow(workflow *models.NewsWorkflow) error {
+	if err := validateNewsWorkflow(workflow); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(workflow)
+	if err != nil {
+		return err
+	}
+	if err := s.settingRepo.Set(SettingNewsWorkflow, string(value)); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.workflow = *workflow
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func (s *newsWorkflowService) ResetWorkflow() (*models.NewsWorkflow, error) {
+	if _, err := s.settingRepo.Delete(SettingNewsWorkflow); err != nil {
+		return nil, err
+	}
+
+	workflow := DefaultNewsWorkflow()
+
+	s.mutex.Lock()
+	s.workflow = workflow
+	s.mutex.Unlock()
+
+	return &workflow, nil
+}
+
+// Sync reloads the stored workflow. An invalid stored workflow keeps the current one in place.
+func (s *newsWorkflowService) Sync() error {
+	value, exists, err := s.settingRepo.Get(SettingNewsWorkflow)
+	if err != nil {
+		return err
+	}
+
+	workflow := DefaultNewsWorkflow()
+	if exists {
+		workflow = models.NewsWorkflow{}
+		if err := json.Unmarshal([]byte(value), &workflow); err != nil {
+			return fmt.Errorf("invalid stored news workflow: %w", err)
+		}
+		if err := validateNewsWorkflow(&workflow); err != nil {
+			return fmt.Errorf("invalid stored news workflow: %w", err)
+		}
+	}
+
+	s.mutex.Lock()
+	s.workflow = workflow
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func validateNewsWorkflow(workflow *models.NewsWorkflow) error {
+	if len(workflow.Transitions) == 0 {
+		return errors.New("the workflow needs at least one transition")
+	}
+
+	seen := make(map[[2]models.NewsStatus]bool)
+	for _, rule := range workflow.Transitions {
+		if !rule.From.IsValid() || !rule.To.IsValid() {
+			return fmt.Errorf("invalid status in transition %s -> %s, use one of %v", rule.From, rule.To, models.ValidNewsStatuses)
+		}
+		if rule.From == rule.To {
+			return fmt.Errorf("transition %s -> %s does not change the status", rule.From, rule.To)
+		}
+
+		key := [2]models.NewsStatus{rule.From, rule.To}
+		if seen[key] {
+			return fmt.Errorf("transition %s -> %s is listed twice", rule.From, rule.To)
+		}
+		seen[key] = true
+
+		if len(rule.Permissions) == 0 {
+			return fmt.Errorf("transition %s -> %s needs at least one permission", rule.From, rule.To)
+		}
+		if err := validatePermissions(rule.Permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
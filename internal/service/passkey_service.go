@@ -0,0 +1,389 @@
+package service
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// passkeySessionTTL is how long the browser has to complete a passkey ceremony
+const passkeySessionTTL = 5 * time.Minute
+
+var (
+	ErrPasskeyNotFound       = errors.New("passkey not found")
+	ErrPasskeyAlreadyExists  = errors.New("passkey is already registered")
+	ErrInvalidPasskeySession = errors.New("invalid or expired passkey session")
+	ErrPasskeyNotAllowed     = errors.New("passkey does not belong to this account")
+	ErrPasskeyNotEnabled     = errors.New("no passkey is registered for this account")
+)
+
+// PasskeyOptions is handed to navigator.credentials.create() or .get(). The session
+// token carries the challenge and has to be sent back with the browser's response.
+type PasskeyOptions struct {
+	Options      map[string]interface{} `json:"options"`
+	SessionToken string                 `json:"session_token"`
+}
+
+// PasskeyService handles WebAuthn registration and login
+type PasskeyService interface {
+	BeginRegistration(userID uint) (*PasskeyOptions, error)
+	FinishRegistration(userID uint, request *models.PasskeyRegistrationRequest) (*models.Passkey, error)
+	List(userID uint) ([]models.Passkey, error)
+	Delete(userID, id uint) error
+	BeginLogin(challengeToken string) (*PasskeyOptions, error) // challengeToken kosong untuk login tanpa password
+	FinishLogin(request *models.PasskeyLoginRequest, client models.ClientInfo) (*LoginResult, error)
+}
+
+type passkeyService struct {
+	passkeyRepo repository.PasskeyRepository
+	userRepo    repository.UserRepository
+	settingRepo repository.SettingRepository
+	authService AuthService
+	revocations TokenRevocationList
+	config      utils.WebAuthnConfig
+	attestation string
+}
+
+// NewPasskeyService creates a new passkey service
+func NewPasskeyService(
+	passkeyRepo repository.PasskeyRepository,
+	userRepo repository.UserRepository,
+	settingRepo repository.SettingRepository,
+	authService AuthService,
+	revocations TokenRevocationList,
+	config utils.WebAuthnConfig,
+	attestation string,
+) PasskeyService {
+	return &passkeyService{
+		passkeyRepo: passkeyRepo,
+		userRepo:    userRepo,
+		settingRepo: settingRepo,
+		authService: authService,
+		revocations: revocations,
+		config:      config,
+		attestation: attestation,
+	}
+}
+
+// BeginRegistration returns the creation options for a new passkey of the user
+func (s *passkeyService) BeginRegistration(userID uint) (*PasskeyOptions, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	existing, err := s.passkeyRepo.ListByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, sessionToken, err := s.newSession(user.ID, utils.PurposePasskeyRegister)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]map[string]interface{}, 0, len(utils.SupportedCOSEAlgorithms))
+	for _, alg := range utils.SupportedCOSEAlgorithms {
+		params = append(params, map[string]interface{}{"type": "public-key", "alg": alg})
+	}
+
+	options := map[string]interface{}{
+		"challenge": challenge,
+		"rp":        map[string]interface{}{"id": s.config.RPID, "name": s.config.RPName},
+		"user": map[string]interface{}{
+			"id":          userHandle(user.ID),
+			"name":        user.Username,
+			"displayName": user.Username,
+		},
+		"pubKeyCredParams":   params,
+		"timeout":            passkeySessionTTL.Milliseconds(),
+		"attestation":        s.attestation,
+		"excludeCredentials": credentialDescriptors(existing),
+		"authenticatorSelection": map[string]interface{}{
+			"residentKey":      "required",
+			"userVerification": "required",
+		},
+	}
+
+	return &PasskeyOptions{Options: options, SessionToken: sessionToken}, nil
+}
+
+// FinishRegistration verifies the attestation and stores the new credential
+func (s *passkeyService) FinishRegistration(userID uint, request *models.PasskeyRegistrationRequest) (*models.Passkey, error) {
+	session, err := s.claimSession(request.SessionToken, utils.PurposePasskeyRegister)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, ErrInvalidPasskeySession
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	clientDataJSON, err := utils.DecodeWebAuthnBytes(request.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, utils.ErrWebAuthnVerification
+	}
+	attestationObject, err := utils.DecodeWebAuthnBytes(request.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, utils.ErrWebAuthnVerification
+	}
+
+	credential, err := utils.VerifyRegistration(s.config, session.Challenge, clientDataJSON, attestationObject, true)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := utils.EncodeWebAuthnBytes(credential.ID)
+	if _, err := s.passkeyRepo.GetByCredentialID(credentialID); err == nil {
+		return nil, ErrPasskeyAlreadyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	name := request.Name
+	if name == "" {
+		name = "Passkey"
+	}
+
+	passkey := &models.Passkey{
+		UserID:            user.ID,
+		Name:              name,
+		CredentialID:      credentialID,
+		PublicKey:         credential.PublicKey,
+		Algorithm:         credential.Algorithm,
+		SignCount:         credential.SignCount,
+		AAGUID:            formatAAGUID(credential.AAGUID),
+		AttestationFormat: credential.AttestationFormat,
+		Transports:        request.Credential.Response.Transports,
+	}
+
+	if err := s.passkeyRepo.Create(passkey); err != nil {
+		return nil, err
+	}
+
+	if !user.PasskeyEnabled {
+		user.PasskeyEnabled = true
+		if err := s.userRepo.Update(user, "passkey_enabled"); err != nil {
+			return nil, err
+		}
+	}
+
+	return passkey, nil
+}
+
+func (s *passkeyService) List(userID uint) ([]models.Passkey, error) {
+	return s.passkeyRepo.ListByUser(userID)
+}
+
+// Delete removes one of the user's passkeys. The account falls back to TOTP or
+// password only login once the last passkey is gone, unless the 2FA policy still
+// needs the passkey as the only second factor of a super admin.
+func (s *passkeyService) Delete(userID, id uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	count, err := s.passkeyRepo.CountByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if count == 1 && user.Role == models.SuperAdmin && !user.TOTPEnabled {
+		value, _, err := s.settingRepo.Get(SettingRequireTwoFactorSuperAdmin)
+		if err != nil {
+			return err
+		}
+		if value == "true" {
+			return ErrTwoFactorRequired
+		}
+	}
+
+	deleted, err := s.passkeyRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPasskeyNotFound
+	}
+
+	if enabled := count > 1; user.PasskeyEnabled != enabled {
+		user.PasskeyEnabled = enabled
+		return s.userRepo.Update(user, "passkey_enabled")
+	}
+
+	return nil
+}
+
+// BeginLogin returns the request options for a passkey login. With a 2FA challenge
+// token the session is bound to that user and only their passkeys are offered;
+// without one the browser lets the user pick any discoverable passkey.
+func (s *passkeyService) BeginLogin(challengeToken string) (*PasskeyOptions, error) {
+	var userID uint
+	allowCredentials := []map[string]interface{}{}
+	userVerification := "required"
+
+	if challengeToken != "" {
+		claims, err := utils.ValidateChallengeToken(challengeToken, utils.PurposeTwoFactorChallenge)
+		if err != nil {
+			return nil, errors.New("invalid or expired challenge token")
+		}
+
+		passkeys, err := s.passkeyRepo.ListByUser(claims.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(passkeys) == 0 {
+			return nil, ErrPasskeyNotEnabled
+		}
+
+		userID = claims.UserID
+		allowCredentials = credentialDescriptors(passkeys)
+		userVerification = "preferred"
+	}
+
+	challenge, sessionToken, err := s.newSession(userID, utils.PurposePasskeyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	options := map[string]interface{}{
+		"challenge":        challenge,
+		"rpId":             s.config.RPID,
+		"timeout":          passkeySessionTTL.Milliseconds(),
+		"allowCredentials": allowCredentials,
+		"userVerification": userVerification,
+	}
+
+	return &PasskeyOptions{Options: options, SessionToken: sessionToken}, nil
+}
+
+// FinishLogin verifies the assertion and signs the user in. A passkey used without
+// a password must have verified the user (PIN or biometrics) to count as two factors.
+func (s *passkeyService) FinishLogin(request *models.PasskeyLoginRequest, client models.ClientInfo) (*LoginResult, error) {
+	session, err := s.claimSession(request.SessionToken, utils.PurposePasskeyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := s.passkeyRepo.GetByCredentialID(request.Credential.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+
+	if session.UserID != 0 && passkey.UserID != session.UserID {
+		return nil, ErrPasskeyNotAllowed
+	}
+
+	response := request.Credential.Response
+	if response.UserHandle != "" && response.UserHandle != userHandle(passkey.UserID) {
+		return nil, ErrPasskeyNotAllowed
+	}
+
+	clientDataJSON, err := utils.DecodeWebAuthnBytes(response.ClientDataJSON)
+	if err != nil {
+		return nil, utils.ErrWebAuthnVerification
+	}
+	authenticatorData, err := utils.DecodeWebAuthnBytes(response.AuthenticatorData)
+	if err != nil {
+		return nil, utils.ErrWebAuthnVerification
+	}
+	signature, err := utils.DecodeWebAuthnBytes(response.Signature)
+	if err != nil {
+		return nil, utils.ErrWebAuthnVerification
+	}
+
+	requireUserVerification := session.UserID == 0
+	signCount, err := utils.VerifyAssertion(s.config, session.Challenge, clientDataJSON, authenticatorData, signature, passkey.PublicKey, passkey.SignCount, requireUserVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passkeyRepo.UpdateSignCount(passkey.ID, signCount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(passkey.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account is disabled")
+	}
+
+	return s.authService.CompleteLogin(user, client)
+}
+
+// newSession creates a random challenge and the signed session token that carries it
+func (s *passkeyService) newSession(userID uint, purpose string) (string, string, error) {
+	challenge, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	sessionToken, err := utils.GeneratePasskeySessionToken(userID, challenge, purpose, passkeySessionTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return challenge, sessionToken, nil
+}
+
+// claimSession validates a session token and revokes it so the challenge cannot be replayed
+func (s *passkeyService) claimSession(sessionToken, purpose string) (*utils.PasskeySessionClaims, error) {
+	claims, err := utils.ValidatePasskeySessionToken(sessionToken, purpose)
+	if err != nil || claims.ID == "" {
+		return nil, ErrInvalidPasskeySession
+	}
+
+	if s.revocations.IsRevoked(utils.HashToken(sessionToken), claims.ID) {
+		return nil, ErrInvalidPasskeySession
+	}
+
+	if err := s.revocations.Revoke(sessionToken, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// userHandle is the opaque WebAuthn user ID: the user ID as 8 big-endian bytes
+func userHandle(userID uint) string {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(userID))
+	return utils.EncodeWebAuthnBytes(handle)
+}
+
+func credentialDescriptors(passkeys []models.Passkey) []map[string]interface{} {
+	descriptors := make([]map[string]interface{}, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		descriptor := map[string]interface{}{"type": "public-key", "id": passkey.CredentialID}
+		if len(passkey.Transports) > 0 {
+			descriptor["transports"] = passkey.Transports
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+// formatAAGUID renders the authenticator model ID in the usual UUID notation
+func formatAAGUID(aaguid []byte) string {
+	if len(aaguid) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", aaguid[0:4], aaguid[4:6], aaguid[6:8], aaguid[8:10], aaguid[10:16])
+}
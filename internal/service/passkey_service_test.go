@@ -0,0 +1,412 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	passkeyTestRPID   = "haslaw.test"
+	passkeyTestOrigin = "https://admin.haslaw.test"
+
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// fakePasskeyRepo keeps passkeys in memory
+type fakePasskeyRepo struct {
+	passkeys []*models.Passkey
+}
+
+func (r *fakePasskeyRepo) Create(passkey *models.Passkey) error {
+	passkey.ID = uint(len(r.passkeys) + 1)
+	r.passkeys = append(r.passkeys, passkey)
+	return nil
+}
+
+func (r *fakePasskeyRepo) GetByCredentialID(credentialID string) (*models.Passkey, error) {
+	for _, passkey := range r.passkeys {
+		if passkey.CredentialID == credentialID {
+			return passkey, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePasskeyRepo) ListByUser(userID uint) ([]models.Passkey, error) {
+	var passkeys []models.Passkey
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userID {
+			passkeys = append(passkeys, *passkey)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *fakePasskeyRepo) CountByUser(userID uint) (int64, error) {
+	passkeys, _ := r.ListByUser(userID)
+	return int64(len(passkeys)), nil
+}
+
+func (r *fakePasskeyRepo) UpdateSignCount(id uint, signCount uint32, usedAt time.Time) error {
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id {
+			passkey.SignCount = signCount
+			passkey.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (r *fakePasskeyRepo) Delete(userID, id uint) (bool, error) {
+	return false, nil
+}
+
+// fakeUserRepo serves a single user; methods the passkey flow does not use panic
+type fakeUserRepo struct {
+	repository.UserRepository
+	user *models.User
+}
+
+func (r *fakeUserRepo) GetByID(id uint) (*models.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user := *r.user
+	return &user, nil
+}
+
+func (r *fakeUserRepo) Update(user *models.User, columns ...string) error {
+	*r.user = *user
+	return nil
+}
+
+type fakeBlacklistRepo struct{}
+
+func (fakeBlacklistRepo) Add(entry *models.BlacklistedToken) error { return nil }
+func (fakeBlacklistRepo) GetActiveSince(since time.Time) ([]models.BlacklistedToken, error) {
+	return nil, nil
+}
+func (fakeBlacklistRepo) CleanupExpiredTokens() error { return nil }
+
+// fakeAuthService stands in for token issuance once the passkey has been verified
+type fakeAuthService struct {
+	AuthService
+}
+
+func (fakeAuthService) CompleteLogin(user *models.User, client models.ClientInfo) (*LoginResult, error) {
+	return &LoginResult{User: user, AccessToken: "access-token"}, nil
+}
+
+// softwareAuthenticator is an ES256 authenticator holding a single credential
+type softwareAuthenticator struct {
+	credentialID []byte
+	key          *ecdsa.PrivateKey
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatal(err)
+	}
+	return &softwareAuthenticator{credentialID: credentialID, key: key}
+}
+
+// coseKey is the CBOR map {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+func (a *softwareAuthenticator) coseKey() []byte {
+	key := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	key = append(key, a.key.X.FillBytes(make([]byte, 32))...)
+	key = append(key, 0x22, 0x58, 0x20)
+	return append(key, a.key.Y.FillBytes(make([]byte, 32))...)
+}
+
+func (a *softwareAuthenticator) authenticatorData(flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(passkeyTestRPID))
+	data := append([]byte{}, rpIDHash[:]...)
+	if attested {
+		flags |= flagAttested
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+// create answers navigator.credentials.create() with a "none" attestation
+func (a *softwareAuthenticator) create(t *testing.T, options *PasskeyOptions) *models.PasskeyRegistrationRequest {
+	t.Helper()
+	clientData := passkeyClientData(t, utils.WebAuthnTypeCreate, options)
+	authData := a.authenticatorData(flagUserPresent|flagUserVerified, true)
+
+	// {"fmt": "none", "attStmt": {}, "authData": authData}
+	attestation := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0}
+	attestation = append(attestation, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x59)
+	attestation = binary.BigEndian.AppendUint16(attestation, uint16(len(authData)))
+	attestation = append(attestation, authData...)
+
+	return &models.PasskeyRegistrationRequest{
+		SessionToken: options.SessionToken,
+		Name:         "Test key",
+		Credential: models.PasskeyCredential{
+			ID:   utils.EncodeWebAuthnBytes(a.credentialID),
+			Type: "public-key",
+			Response: models.PasskeyAuthenticatorResponse{
+				ClientDataJSON:    utils.EncodeWebAuthnBytes(clientData),
+				AttestationObject: utils.EncodeWebAuthnBytes(attestation),
+			},
+		},
+	}
+}
+
+// get answers navigator.credentials.get() after bumping the sign counter by step
+func (a *softwareAuthenticator) get(t *testing.T, options *PasskeyOptions, userID uint, flags byte, step int) *models.PasskeyLoginRequest {
+	t.Helper()
+	a.signCount = uint32(int(a.signCount) + step)
+	clientData := passkeyClientData(t, utils.WebAuthnTypeGet, options)
+	authData := a.authenticatorData(flags, false)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &models.PasskeyLoginRequest{
+		SessionToken: options.SessionToken,
+		Credential: models.PasskeyCredential{
+			ID:   utils.EncodeWebAuthnBytes(a.credentialID),
+			Type: "public-key",
+			Response: models.PasskeyAuthenticatorResponse{
+				ClientDataJSON:    utils.EncodeWebAuthnBytes(clientData),
+				AuthenticatorData: utils.EncodeWebAuthnBytes(authData),
+				Signature:         utils.EncodeWebAuthnBytes(signature),
+				UserHandle:        userHandle(userID),
+			},
+		},
+	}
+}
+
+func passkeyClientData(t *testing.T, clientDataType string, options *PasskeyOptions) []byte {
+	t.Helper()
+	data, err := json.Marshal(utils.CollectedClientData{
+		Type:      clientDataType,
+		Challenge: options.Options["challenge"].(string),
+		Origin:    passkeyTestOrigin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestPasskeyService(t *testing.T) (PasskeyService, *fakePasskeyRepo, *models.User) {
+	t.Helper()
+	ring, err := utils.NewKeyRing(utils.KeyRingConfig{Algorithm: utils.AlgorithmHS256, Secret: "passkey-test-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	utils.SetKeyRing(ring)
+	t.Cleanup(func() { utils.SetKeyRing(nil) })
+
+	revocations, err := NewTokenRevocationList(fakeBlacklistRepo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &models.User{ID: 42, Username: "editor", Role: models.Editor, IsActive: true}
+	passkeys := &fakePasskeyRepo{}
+	config := utils.WebAuthnConfig{RPID: passkeyTestRPID, RPName: "HasLaw", Origins: []string{passkeyTestOrigin}}
+	service := NewPasskeyService(passkeys, &fakeUserRepo{user: user}, nil, fakeAuthService{}, revocations, config, "none")
+
+	return service, passkeys, user
+}
+
+// register runs a complete registration ceremony for the user
+func register(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) *models.Passkey {
+	t.Helper()
+	options, err := service.BeginRegistration(user.ID)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	passkey, err := service.FinishRegistration(user.ID, authenticator.create(t, options))
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return passkey
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	service, passkeys, user := newTestPasskeyService(t)
+	authenticator := newSoftwareAuthenticator(t)
+
+	passkey := register(t, service, user, authenticator)
+	if passkey.UserID != user.ID || passkey.Algorithm != utils.COSEAlgES256 || passkey.AttestationFormat != "none" {
+		t.Errorf("passkey = %+v", passkey)
+	}
+	if len(passkeys.passkeys) != 1 {
+		t.Errorf("stored %d passkeys, want 1", len(passkeys.passkeys))
+	}
+	if !user.PasskeyEnabled {
+		t.Error("user.PasskeyEnabled was not set")
+	}
+}
+
+func TestPasskeyRegistrationRejectsReplayedSession(t *testing.T) {
+	service, _, user := newTestPasskeyService(t)
+
+	options, err := service.BeginRegistration(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.FinishRegistration(user.ID, newSoftwareAuthenticator(t).create(t, options)); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+
+	_, err = service.FinishRegistration(user.ID, newSoftwareAuthenticator(t).create(t, options))
+	if !errors.Is(err, ErrInvalidPasskeySession) {
+		t.Fatalf("err = %v, want ErrInvalidPasskeySession", err)
+	}
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	service, passkeys, user := newTestPasskeyService(t)
+	authenticator := newSoftwareAuthenticator(t)
+	register(t, service, user, authenticator)
+
+	options, err := service.BeginLogin("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent|flagUserVerified, 1), models.ClientInfo{})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if result.User.ID != user.ID || result.AccessToken == "" {
+		t.Errorf("result = %+v", result)
+	}
+	if passkeys.passkeys[0].SignCount != 1 || passkeys.passkeys[0].LastUsedAt == nil {
+		t.Errorf("sign count = %d, last used = %v", passkeys.passkeys[0].SignCount, passkeys.passkeys[0].LastUsedAt)
+	}
+}
+
+func TestPasskeyLoginAsSecondFactorAllowsUserPresenceOnly(t *testing.T) {
+	service, _, user := newTestPasskeyService(t)
+	authenticator := newSoftwareAuthenticator(t)
+	register(t, service, user, authenticator)
+
+	challengeToken, err := utils.GenerateChallengeToken(user.ID, user.Username, utils.PurposeTwoFactorChallenge, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options, err := service.BeginLogin(challengeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent, 1), models.ClientInfo{}); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+}
+
+func TestPasskeyLoginRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		login func(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) error
+		want  error
+	}{
+		{
+			name: "replayed session token",
+			login: func(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) error {
+				options, err := service.BeginLogin("")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent|flagUserVerified, 1), models.ClientInfo{}); err != nil {
+					t.Fatalf("first FinishLogin: %v", err)
+				}
+				_, err = service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent|flagUserVerified, 1), models.ClientInfo{})
+				return err
+			},
+			want: ErrInvalidPasskeySession,
+		},
+		{
+			name: "sign counter went backwards",
+			login: func(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) error {
+				options, err := service.BeginLogin("")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent|flagUserVerified, 5), models.ClientInfo{}); err != nil {
+					t.Fatalf("first FinishLogin: %v", err)
+				}
+				options, err = service.BeginLogin("")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent|flagUserVerified, -2), models.ClientInfo{})
+				return err
+			},
+			want: utils.ErrWebAuthnVerification,
+		},
+		{
+			name: "missing user verification on passwordless login",
+			login: func(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) error {
+				options, err := service.BeginLogin("")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = service.FinishLogin(authenticator.get(t, options, user.ID, flagUserPresent, 1), models.ClientInfo{})
+				return err
+			},
+			want: utils.ErrWebAuthnVerification,
+		},
+		{
+			name: "user handle of another account",
+			login: func(t *testing.T, service PasskeyService, user *models.User, authenticator *softwareAuthenticator) error {
+				options, err := service.BeginLogin("")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = service.FinishLogin(authenticator.get(t, options, user.ID+1, flagUserPresent|flagUserVerified, 1), models.ClientInfo{})
+				return err
+			},
+			want: ErrPasskeyNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, user := newTestPasskeyService(t)
+			authenticator := newSoftwareAuthenticator(t)
+			register(t, service, user, authenticator)
+
+			if err := tt.login(t, service, user, authenticator); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// bcryptMaxLength is the number of bytes bcrypt actually uses, the rest would be silently ignored
+const bcryptMaxLength = 72
+
+// Password policy rules, returned to the frontend so it can show each violated rule
+const (
+	PasswordRuleMinLength        = "min_length"
+	PasswordRuleMaxLength        = "max_length"
+	PasswordRuleUppercase        = "uppercase"
+	PasswordRuleLowercase        = "lowercase"
+	PasswordRuleDigit            = "digit"
+	PasswordRuleSymbol           = "symbol"
+	PasswordRuleContainsUsername = "contains_username"
+	PasswordRuleContainsEmail    = "contains_email"
+	PasswordRuleBreached         = "breached"
+	PasswordRuleReused           = "reused"
+)
+
+// PasswordPolicyConfig controls which rules a new password must satisfy
+type PasswordPolicyConfig struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+	HistorySize      int  `json:"history_size"`
+}
+
+// PasswordViolation is one rule the password does not satisfy
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError lists every violated rule, not only the first one
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "password does not meet the policy: " + strings.Join(messages, "; ")
+}
+
+// PasswordPolicy validates new passwords and remembers used password hashes
+type PasswordPolicy interface {
+	Rules() PasswordPolicyConfig
+	Validate(password string, user *models.User) error // user berisi username/email baru; ID 0 untuk akun baru
+	Remember(userID uint, passwordHash string) error   // Dipanggil setelah password tersimpan
+}
+
+type passwordPolicy struct {
+	config      PasswordPolicyConfig
+	historyRepo repository.PasswordHistoryRepository
+	breached    BreachedPasswordSource
+}
+
+// NewPasswordPolicy creates a new password policy
+func NewPasswordPolicy(config PasswordPolicyConfig, historyRepo repository.PasswordHistoryRepository, breached BreachedPasswordSource) PasswordPolicy {
+	return &passwordPolicy{
+		config:      config,
+		historyRepo: historyRepo,
+		breached:    breached,
+	}
+}
+
+func (p *passwordPolicy) Rules() PasswordPolicyConfig {
+	return p.config
+}
+
+func (p *passwordPolicy) Validate(password string, user *models.User) error {
+	var violations []PasswordViolation
+	violate := func(rule, message string) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: message})
+	}
+
+	if len([]rune(password)) < p.config.MinLength {
+		violate(PasswordRuleMinLength, fmt.Sprintf("must be at least %d characters long", p.config.MinLength))
+	}
+	if len(password) > bcryptMaxLength {
+		violate(PasswordRuleMaxLength, fmt.Sprintf("must be at most %d bytes long", bcryptMaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.config.RequireUppercase && !hasUpper {
+		violate(PasswordRuleUppercase, "must contain an uppercase letter")
+	}
+	if p.config.RequireLowercase && !hasLower {
+		violate(PasswordRuleLowercase, "must contain a lowercase letter")
+	}
+	if p.config.RequireDigit && !hasDigit {
+		violate(PasswordRuleDigit, "must contain a digit")
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		violate(PasswordRuleSymbol, "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if user != nil {
+		if len(user.Username) >= 3 && strings.Contains(lowered, strings.ToLower(user.Username)) {
+			violate(PasswordRuleContainsUsername, "must not contain the username")
+		}
+		if local, _, _ := strings.Cut(user.Email, "@"); len(local) >= 3 && strings.Contains(lowered, strings.ToLower(local)) {
+			violate(PasswordRuleContainsEmail, "must not contain the email address")
+		}
+	}
+
+	if p.breached != nil {
+		breached, err := isBreached(p.breached, password)
+		if err != nil {
+			// Lookup yang gagal tidak boleh memblokir perubahan password
+			log.Printf("Warning: Breached password lookup failed: %v", err)
+		} else if breached {
+			violate(PasswordRuleBreached, "appears in a list of breached passwords, choose another one")
+		}
+	}
+
+	// Cek reuse paling akhir karena bcrypt lambat, dan hanya jika aturan lain sudah lolos
+	if len(violations) == 0 && user != nil && user.ID != 0 {
+		reused, err := p.isReused(password, user)
+		if err != nil {
+			return err
+		}
+		if reused {
+			violate(PasswordRuleReused, fmt.Sprintf("must not be one of your last %d passwords", p.config.HistorySize))
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (p *passwordPolicy) Remember(userID uint, passwordHash string) error {
+	if p.config.HistorySize <= 0 {
+		return nil
+	}
+
+	if err := p.historyRepo.Add(userID, passwordHash); err != nil {
+		return err
+	}
+	return p.historyRepo.Prune(userID, p.config.HistorySize)
+}
+
+// isReused compares the password against the current hash and the stored history
+func (p *passwordPolicy) isReused(password string, user *models.User) (bool, error) {
+	if p.config.HistorySize <= 0 {
+		return false, nil
+	}
+
+	if user.Password != "" && utils.CheckPasswordHash(password, user.Password) {
+		return true, nil
+	}
+
+	history, err := p.historyRepo.GetRecent(user.ID, p.config.HistorySize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range history {
+		if utils.CheckPasswordHash(password, entry.PasswordHash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
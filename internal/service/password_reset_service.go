@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidResetToken = errors.New("reset token is invalid or has expired")
+
+// PasswordResetService handles the forgot/reset password flow
+type PasswordResetService interface {
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) (*models.User, error)
+	CleanupExpiredTokens() error
+}
+
+type passwordResetService struct {
+	userRepo    repository.UserRepository
+	resetRepo   repository.PasswordResetRepository
+	authService AuthService
+	passwords   PasswordPolicy
+	mailer      utils.Mailer
+	resetURL    string
+	tokenTTL    time.Duration
+}
+
+// NewPasswordResetService creates a new password reset service
+func NewPasswordResetService(
+	userRepo repository.UserRepository,
+	resetRepo repository.PasswordResetRepository,
+	authService AuthService,
+	passwords PasswordPolicy,
+	mailer utils.Mailer,
+	resetURL string,
+	tokenTTL time.Duration,
+) PasswordResetService {
+	return &passwordResetService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		authService: authService,
+		passwords:   passwords,
+		mailer:      mailer,
+		resetURL:    resetURL,
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// ForgotPassword issues a single-use reset token and emails it to the user.
+// Unknown or disabled accounts are silently ignored so the endpoint cannot be
+// used to discover registered emails.
+func (s *passwordResetService) ForgotPassword(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	// Hanya token terbaru yang berlaku
+	if err := s.resetRepo.InvalidateForUser(user.ID); err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(s.tokenTTL),
+	}
+	if err := s.resetRepo.Create(resetToken); err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(user.Email, "Reset password akun Haslaw", s.buildEmailBody(user, token)); err != nil {
+		log.Printf("Warning: Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes
+// every outstanding token of the user. Returns the user whose password was reset.
+func (s *passwordResetService) ResetPassword(token, newPassword string) (*models.User, error) {
+	resetToken, err := s.resetRepo.GetValidByHash(utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidResetToken
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(resetToken.UserID)
+	if err != nil {
+		return nil, ErrInvalidResetToken
+	}
+
+	// Dicek sebelum token dipakai, agar password yang ditolak tidak menghabiskan token
+	if err := s.passwords.Validate(newPassword, user); err != nil {
+		return nil, err
+	}
+
+	consumed, err := s.resetRepo.MarkUsed(resetToken.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidResetToken
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	user.MustChangePassword = false
+	if err := s.userRepo.Update(user, "password", "must_change_password"); err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Remember(user.ID, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.RevokeAllUserTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// CleanupExpiredTokens deletes reset tokens that can no longer be used
+func (s *passwordResetService) CleanupExpiredTokens() error {
+	return s.resetRepo.CleanupExpired()
+}
+
+func (s *passwordResetService) buildEmailBody(user *models.User, token string) string {
+	link := s.resetURL
+	if parsed, err := url.Parse(s.resetURL); err == nil {
+		query := parsed.Query()
+		query.Set("token", token)
+		parsed.RawQuery = query.Encode()
+		link = parsed.String()
+	}
+
+	return fmt.Sprintf(`Halo %s,
+
+Kami menerima permintaan untuk mereset password akun Haslaw Anda.
+Buka tautan berikut untuk membuat password baru:
+
+%s
+
+Tautan ini hanya dapat digunakan sekali dan berlaku selama %s.
+Jika Anda tidak meminta reset password, abaikan email ini.
+`, user.Username, link, s.tokenTTL)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"log"
+	"regexp"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound  = errors.New("role not found")
+	ErrRoleExists    = errors.New("role already exists")
+	ErrRoleInUse     = errors.New("role is still assigned to users")
+	ErrRoleImmutable = errors.New("this role cannot be changed")
+	ErrForbidden     = errors.New("you do not have permission to perform this action")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// RoleService manages stored roles and resolves the permissions of a role. Permissions
+// are cached in memory and reloaded by Sync, so role changes made on another instance
+// apply without a restart.
+type RoleService interface {
+	EnsureBuiltInRoles() error
+	ListRoles() ([]models.Role, error)
+	GetRole(name models.UserRole) (*models.Role, error)
+	CreateRole(actor models.UserRole, request *models.CreateRoleRequest) (*models.Role, error)
+	UpdateRole(actor models.UserRole, name models.UserRole, request *models.UpdateRoleRequest) (*models.Role, error)
+	DeleteRole(name models.UserRole) error
+	PermissionsFor(role models.UserRole) ([]models.Permission, error) // Cached, dipakai di setiap request
+	CanGrant(actor models.UserRole, permissions []models.Permission) error
+	Sync() error
+}
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+
+	mutex sync.RWMutex
+	cache map[models.UserRole][]models.Permission
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		cache:    make(map[models.UserRole][]models.Permission),
+	}
+}
+
+// EnsureBuiltInRoles creates missing built-in roles. Existing roles keep their
+// (possibly customized) permissions, except super_admin which always has every permission.
+func (s *roleService) EnsureBuiltInRoles() error {
+	for _, name := range models.ValidUserRoles {
+		role, err := s.roleRepo.GetByName(name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if role == nil {
+			role = &models.Role{
+				Name:        name,
+				Description: fmt.Sprintf("Built-in %s role", name),
+				Permissions: models.BuiltInRolePermissions[name],
+				IsBuiltIn:   true,
+			}
+			if err := s.roleRepo.Create(role); err != nil {
+				return err
+			}
+			log.Printf("Role %s created", name)
+			continue
+		}
+
+		if name == models.SuperAdmin && !models.HasPermission(role.Permissions, models.PermissionAll) {
+			role.Permissions = []models.Permission{models.PermissionAll}
+			if err := s.roleRepo.Update(role); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.invalidate()
+	return nil
+}
+
+func (s *roleService) ListRoles() ([]models.Role, error) {
+	return s.roleRepo.List()
+}
+
+func (s *roleService) GetRole(name models.UserRole) (*models.Role, error) {
+	role, err := s.roleRepo.GetByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleService) CreateRole(actor models.UserRole, request *models.CreateRoleRequest) (*models.Role, error) {
+	if !roleNamePattern.MatchString(string(request.Name)) {
+		return nil, errors.New("role name must be lowercase letters, digits or underscores")
+	}
+
+	if err := validatePermissions(request.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := s.CanGrant(actor, request.Permissions); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.roleRepo.GetByName(request.Name); err == nil {
+		return nil, ErrRoleExists
+	}
+
+	role := &models.Role{
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: request.Permissions,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (s *roleService) UpdateRole(actor models.UserRole, name models.UserRole, request *models.UpdateRoleRequest) (*models.Role, error) {
+	if name == models.SuperAdmin {
+		return nil, ErrRoleImmutable
+	}
+
+	if err := validatePermissions(request.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := s.CanGrant(actor, request.Permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.GetRole(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Role yang lebih kuat dari actor juga tidak boleh dikurangi permission-nya
+	if err := s.CanGrant(actor, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	role.Description = request.Description
+	role.Permissions = request.Permissions
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return role, nil
+}
+
+func (s *roleService) DeleteRole(name models.UserRole) error {
+	role, err := s.GetRole(name)
+	if err != nil {
+		return err
+	}
+
+	if role.IsBuiltIn {
+		return ErrRoleImmutable
+	}
+
+	count, err := s.userRepo.CountByRole(name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(name); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// PermissionsFor returns the permissions of a role. Unknown roles have no permissions.
+func (s *roleService) PermissionsFor(name models.UserRole) ([]models.Permission, error) {
+	s.mutex.RLock()
+	permissions, cached := s.cache[name]
+	s.mutex.RUnlock()
+	if cached {
+		return permissions, nil
+	}
+
+	role, err := s.roleRepo.GetByName(name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	permissions = []models.Permission{}
+	if role != nil {
+		permissions = role.Permissions
+	}
+
+	s.mutex.Lock()
+	s.cache[name] = permissions
+	s.mutex.Unlock()
+
+	return permissions, nil
+}
+
+// CanGrant prevents privilege escalation: a user can only hand out permissions they hold themselves
+func (s *roleService) CanGrant(actor models.UserRole, permissions []models.Permission) error {
+	granted, err := s.PermissionsFor(actor)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !models.HasPermission(granted, permission) {
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// Sync reloads the permissions of every stored role
+func (s *roleService) Sync() error {
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return err
+	}
+
+	cache := make(map[models.UserRole][]models.Permission, len(roles))
+	for _, role := range roles {
+		cache[role.Name] = role.Permissions
+	}
+
+	s.mutex.Lock()
+	s.cache = cache
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func (s *roleService) invalidate() {
+	s.mutex.Lock()
+	s.cache = make(map[models.UserRole][]models.Permission)
+	s.mutex.Unlock()
+}
+
+func validatePermissions(permissions []models.Permission) error {
+	for _, permission := range permissions {
+		if !permission.IsValid() {
+			return fmt.Errorf("unknown permission %q", permission)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"time"
+)
+
+// SecurityEventService keeps the login history and security event feed of each user
+type SecurityEventService interface {
+	Record(event *models.SecurityEvent)
+	ListForUser(userID uint, filter *models.SecurityEventFilter, page, limit int) ([]models.SecurityEvent, *utils.PaginationMeta, error)
+	CleanupExpired() error // Menghapus kejadian yang lebih lama dari masa simpan
+}
+
+type securityEventService struct {
+	eventRepo repository.SecurityEventRepository
+	userRepo  repository.UserRepository
+	retention time.Duration
+}
+
+// NewSecurityEventService creates a new security event service. Events older than
+// retention are removed by CleanupExpired, 0 keeps them forever.
+func NewSecurityEventService(eventRepo repository.SecurityEventRepository, userRepo repository.UserRepository, retention time.Duration) SecurityEventService {
+	return &securityEventService{
+		eventRepo: eventRepo,
+		userRepo:  userRepo,
+		retention: retention,
+	}
+}
+
+// Record stores the event. Failed logins for a known username are attached to that
+// user, so password guessing shows up in the victim's feed. A successful login also
+// updates the user's last login. Write errors are logged, the request already happened.
+func (s *securityEventService) Record(event *models.SecurityEvent) {
+	if event.UserID == nil && event.Username != "" {
+		if user, err := s.userRepo.GetByUsername(event.Username); err == nil {
+			event.UserID = &user.ID
+		}
+	}
+
+	event.Detail = truncate(event.Detail, 255)
+	event.UserAgent = truncate(event.UserAgent, 512)
+
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("[TRACE: %s] Warning: Failed to write security event (%s): %v", event.TraceID, event.Type, err)
+		return
+	}
+
+	if event.Type == models.SecurityEventLogin && event.Success && event.UserID != nil {
+		if err := s.userRepo.UpdateLastLogin(*event.UserID, event.CreatedAt, event.IPAddress); err != nil {
+			log.Printf("[TRACE: %s] Warning: Failed to update last login of user %d: %v", event.TraceID, *event.UserID, err)
+		}
+	}
+}
+
+func (s *securityEventService) ListForUser(userID uint, filter *models.SecurityEventFilter, page, limit int) ([]models.SecurityEvent, *utils.PaginationMeta, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, nil, ErrUserNotFound
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	events, total, err := s.eventRepo.ListByUser(userID, filter, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return events, meta, nil
+}
+
+func (s *securityEventService) CleanupExpired() error {
+	if s.retention <= 0 {
+		return nil
+	}
+	_, err := s.eventRepo.DeleteOlderThan(time.Now().Add(-s.retention))
+	return err
+}
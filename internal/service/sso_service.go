@@ -0,0 +1,259 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ssoStateTTL is how long the user has to finish signing in at the identity provider
+const ssoStateTTL = 10 * time.Minute
+
+var (
+	ErrSSOInvalidState     = errors.New("SSO login is invalid or has expired, please try again")
+	ErrSSOEmailNotVerified = errors.New("the identity provider did not return a verified email")
+	ErrSSOAccountNotLinked = errors.New("no account exists for this identity")
+)
+
+var usernameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// SSOConfig controls how identities from the provider map to local users
+type SSOConfig struct {
+	AutoProvision        bool            // Buat user baru jika email belum terdaftar
+	DefaultRole          models.UserRole // Role untuk user yang dibuat otomatis
+	RequireVerifiedEmail bool            // Hanya hubungkan lewat email jika email_verified = true
+}
+
+// SSOLogin is the start of an SSO login: the browser is redirected to AuthURL and
+// StateToken is kept in a cookie until the callback
+type SSOLogin struct {
+	AuthURL    string
+	StateToken string
+}
+
+// SSOService signs users in through an OpenID Connect provider
+type SSOService interface {
+	Begin() (*SSOLogin, error)
+	Complete(code, state, stateToken string, client models.ClientInfo) (*LoginResult, error)
+}
+
+type ssoService struct {
+	provider     *utils.OIDCProvider
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	roleService  RoleService
+	authService  AuthService
+	config       SSOConfig
+}
+
+// NewSSOService creates a new SSO service
+func NewSSOService(
+	provider *utils.OIDCProvider,
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	roleService RoleService,
+	authService AuthService,
+	config SSOConfig,
+) SSOService {
+	return &ssoService{
+		provider:     provider,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		roleService:  roleService,
+		authService:  authService,
+		config:       config,
+	}
+}
+
+func (s *ssoService) Begin() (*SSOLogin, error) {
+	state, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := utils.NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(state, nonce, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	stateToken, err := utils.GenerateOIDCStateToken(state, nonce, verifier, ssoStateTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSOLogin{AuthURL: authURL, StateToken: stateToken}, nil
+}
+
+// Complete redeems the authorization code and signs in the linked user. Accounts
+// with TOTP or a passkey still get a 2FA challenge, like a password login.
+func (s *ssoService) Complete(code, state, stateToken string, client models.ClientInfo) (*LoginResult, error) {
+	pending, err := utils.ValidateOIDCStateToken(stateToken)
+	if err != nil || subtle.ConstantTimeCompare([]byte(pending.State), []byte(state)) != 1 {
+		return nil, ErrSSOInvalidState
+	}
+
+	rawIDToken, err := s.provider.Exchange(code, pending.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.provider.VerifyIDToken(rawIDToken, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account is disabled")
+	}
+
+	if user.HasSecondFactor() {
+		challengeToken, err := utils.GenerateChallengeToken(user.ID, user.Username, utils.PurposeTwoFactorChallenge, twoFactorChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			User:              user,
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	return s.authService.CompleteLogin(user, client)
+}
+
+// resolveUser finds the user linked to the identity, links an existing user with the
+// same verified email, or provisions a new user when enabled
+func (s *ssoService) resolveUser(claims *utils.IDTokenClaims) (*models.User, error) {
+	issuer := s.provider.Issuer()
+	now := time.Now()
+
+	identity, err := s.identityRepo.GetBySubject(issuer, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.TouchLastLogin(identity.ID, now); err != nil {
+			log.Printf("Warning: Failed to update last login of identity %d: %v", identity.ID, err)
+		}
+		return s.userRepo.GetByID(identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || (s.config.RequireVerifiedEmail && !bool(claims.EmailVerified)) {
+		return nil, ErrSSOEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByEmail(claims.Email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if !s.config.AutoProvision {
+			return nil, ErrSSOAccountNotLinked
+		}
+		if user, err = s.provision(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	identity = &models.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      issuer,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		return nil, err
+	}
+
+	log.Printf("SSO identity %s linked to user %d", claims.Subject, user.ID)
+	return user, nil
+}
+
+// provision creates a user in the configured role. The random password cannot be
+// guessed; the user can set one later through the forgot password flow.
+func (s *ssoService) provision(claims *utils.IDTokenClaims) (*models.User, error) {
+	if s.config.DefaultRole == models.SuperAdmin {
+		return nil, errors.New("SSO cannot provision super admins")
+	}
+	if _, err := s.roleService.GetRole(s.config.DefaultRole); err != nil {
+		return nil, fmt.Errorf("SSO default role %q: %w", s.config.DefaultRole, err)
+	}
+
+	username, err := s.availableUsername(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := utils.HashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: username,
+		Email:    claims.Email,
+		Password: hashedPassword,
+		Role:     s.config.DefaultRole,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	log.Printf("User '%s' provisioned through SSO with role %s", user.Username, user.Role)
+	return user, nil
+}
+
+// availableUsername derives a username from preferred_username or the email and adds a suffix when it is taken
+func (s *ssoService) availableUsername(claims *utils.IDTokenClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" || strings.Contains(base, "@") {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	base = usernameUnsafeChars.ReplaceAllString(base, "")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 2; i <= 100; i++ {
+		if _, err := s.userRepo.GetByUsername(candidate); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return candidate, nil
+			}
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return "", errors.New("could not find an available username")
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("a tag with this name or slug already exists")
+	ErrUnknownTag  = errors.New("unknown tag, create it first")
+)
+
+// TagService manages news tags
+type TagService interface {
+	List(publishedOnly bool) ([]models.TagCount, error) // publishedOnly menghitung berita yang sedang tayang saja
+	Get(id uint) (*models.Tag, error)
+	Create(request *models.TaxonomyRequest) (*models.Tag, error)
+	Update(id uint, request *models.TaxonomyRequest) (*models.Tag, error)
+	Delete(id uint) error // Tag dilepas dari semua berita
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+func (s *tagService) List(publishedOnly bool) ([]models.TagCount, error) {
+	tags, err := s.tagRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.tagRepo.CountNews(publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.TagCount, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, models.TagCount{Tag: tag, NewsCount: counts[tag.ID]})
+	}
+	return result, nil
+}
+
+func (s *tagService) Get(id uint) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) Create(request *models.TaxonomyRequest) (*models.Tag, error) {
+	name, slug, err := taxonomyNameAndSlug(request)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.tagRepo.Taken(name, slug, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrTagExists
+	}
+
+	tag := &models.Tag{
+		Name:        name,
+		Slug:        slug,
+		Description: request.Description,
+		SortOrder:   request.SortOrder,
+	}
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) Update(id uint, request *models.TaxonomyRequest) (*models.Tag, error) {
+	tag, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, slug, err := taxonomyNameAndSlug(request)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.tagRepo.Taken(name, slug, id)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrTagExists
+	}
+
+	tag.Name = name
+	tag.Slug = slug
+	tag.Description = request.Description
+	tag.SortOrder = request.SortOrder
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagService) Delete(id uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	return s.tagRepo.Delete(id)
+}
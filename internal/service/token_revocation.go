@@ -0,0 +1,137 @@
+package service
+
+import (
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"sync"
+	"time"
+)
+
+// revocationSyncOverlap re-reads entries slightly older than the last sync so rows
+// committed by another instance while the previous sync ran are not missed
+const revocationSyncOverlap = 5 * time.Second
+
+// TokenRevocationList answers whether a token has been revoked without a database
+// query. Entries are indexed by token hash and jti, loaded from blacklisted_tokens
+// and dropped from memory once the token would have expired anyway.
+type TokenRevocationList interface {
+	Revoke(token, jti string, userID uint, expiresAt time.Time) error
+	IsRevoked(tokenHash, jti string) bool
+	Sync() error           // Memuat pencabutan baru dari database, termasuk dari instance lain
+	CleanupExpired() error // Menghapus entry yang sudah expire dari memory dan database
+}
+
+type tokenRevocationList struct {
+	blacklistRepo repository.BlacklistRepository
+
+	mutex    sync.RWMutex
+	byHash   map[string]time.Time
+	byJTI    map[string]time.Time
+	lastSync time.Time
+}
+
+// NewTokenRevocationList creates the revocation list and loads every unexpired entry
+func NewTokenRevocationList(blacklistRepo repository.BlacklistRepository) (TokenRevocationList, error) {
+	list := &tokenRevocationList{
+		blacklistRepo: blacklistRepo,
+		byHash:        make(map[string]time.Time),
+		byJTI:         make(map[string]time.Time),
+	}
+
+	if err := list.Sync(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Revoke stores the revocation and makes it visible to this instance immediately
+func (l *tokenRevocationList) Revoke(token, jti string, userID uint, expiresAt time.Time) error {
+	entry := &models.BlacklistedToken{
+		TokenHash: utils.HashToken(token),
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+	if err := l.blacklistRepo.Add(entry); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	l.add(entry)
+	l.mutex.Unlock()
+
+	return nil
+}
+
+func (l *tokenRevocationList) IsRevoked(tokenHash, jti string) bool {
+	now := time.Now()
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if expiresAt, exists := l.byHash[tokenHash]; exists && now.Before(expiresAt) {
+		return true
+	}
+	if jti == "" {
+		return false
+	}
+	expiresAt, exists := l.byJTI[jti]
+	return exists && now.Before(expiresAt)
+}
+
+func (l *tokenRevocationList) Sync() error {
+	l.mutex.RLock()
+	since := l.lastSync
+	l.mutex.RUnlock()
+
+	if !since.IsZero() {
+		since = since.Add(-revocationSyncOverlap)
+	}
+
+	startedAt := time.Now()
+	entries, err := l.blacklistRepo.GetActiveSince(since)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	for i := range entries {
+		l.add(&entries[i])
+	}
+	l.lastSync = startedAt
+	l.mutex.Unlock()
+
+	return nil
+}
+
+func (l *tokenRevocationList) CleanupExpired() error {
+	now := time.Now()
+
+	l.mutex.Lock()
+
+	for hash, expiresAt := range l.byHash {
+		if !now.Before(expiresAt) {
+			delete(l.byHash, hash)
+		}
+	}
+	for jti, expiresAt := range l.byJTI {
+		if !now.Before(expiresAt) {
+			delete(l.byJTI, jti)
+		}
+	}
+	l.mutex.Unlock()
+
+	return l.blacklistRepo.CleanupExpiredTokens()
+}
+
+// add must be called with the mutex held
+func (l *tokenRevocationList) add(entry *models.BlacklistedToken) {
+	if entry.TokenHash != "" {
+		l.byHash[entry.TokenHash] = entry.ExpiresAt
+	}
+	if entry.JTI != "" {
+		l.byJTI[entry.JTI] = entry.ExpiresAt
+	}
+}
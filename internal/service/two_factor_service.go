@@ -0,0 +1,273 @@
+package service
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+	"strconv"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrInvalidTwoFactorCode   = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyActive = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotActive     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired      = errors.New("two-factor authentication is required for this account")
+)
+
+// TwoFactorSetup is returned when a user starts TOTP enrollment
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorPolicy describes the global 2FA requirements
+type TwoFactorPolicy struct {
+	RequireForSuperAdmins bool `json:"require_for_super_admins"`
+}
+
+// TwoFactorService handles TOTP enrollment, verification and recovery codes
+type TwoFactorService interface {
+	Setup(userID uint) (*TwoFactorSetup, error)
+	Enable(userID uint, code string) ([]string, error) // Returns recovery codes
+	Disable(userID uint, password, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	VerifyLogin(challengeToken, code string, client models.ClientInfo) (*LoginResult, error)
+	GetPolicy() (*TwoFactorPolicy, error)
+	SetRequireForSuperAdmins(require bool) error
+}
+
+type twoFactorService struct {
+	userRepo     repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	settingRepo  repository.SettingRepository
+	authService  AuthService
+	throttler    LoginThrottler
+	issuer       string
+}
+
+// NewTwoFactorService creates a new two-factor service
+func NewTwoFactorService(
+	userRepo repository.UserRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	settingRepo repository.SettingRepository,
+	authService AuthService,
+	throttler LoginThrottler,
+	issuer string,
+) TwoFactorService {
+	return &twoFactorService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		settingRepo:  settingRepo,
+		authService:  authService,
+		throttler:    throttler,
+		issuer:       issuer,
+	}
+}
+
+// Setup generates a new (not yet active) TOTP secret for the user
+func (s *twoFactorService) Setup(userID uint) (*TwoFactorSetup, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyActive
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user, "totp_secret", "totp_last_step"); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable verifies the first code from the authenticator app and activates 2FA
+func (s *twoFactorService) Enable(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyActive
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+
+	if !s.verifyTOTP(user, code) {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPEnabled = true
+	if err := s.userRepo.Update(user, "totp_enabled"); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(user.ID)
+}
+
+// Disable turns 2FA off after re-checking the password and a current code
+func (s *twoFactorService) Disable(userID uint, password, code string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotActive
+	}
+
+	if !utils.CheckPasswordHash(password, user.Password) {
+		return errors.New("invalid password")
+	}
+
+	// Super admin dengan passkey tetap memenuhi kebijakan 2FA tanpa TOTP
+	if user.Role == models.SuperAdmin && !user.PasskeyEnabled {
+		policy, err := s.GetPolicy()
+		if err != nil {
+			return err
+		}
+		if policy.RequireForSuperAdmins {
+			return ErrTwoFactorRequired
+		}
+	}
+
+	if !s.verifyTOTP(user, code) {
+		return ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user, "totp_enabled", "totp_secret", "totp_last_step"); err != nil {
+		return err
+	}
+
+	return s.recoveryRepo.DeleteForUser(user.ID)
+}
+
+// RegenerateRecoveryCodes invalidates old recovery codes and returns a fresh set
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.TOTPEnabled {
+		return nil, ErrTwoFactorNotActive
+	}
+
+	if !s.verifyTOTP(user, code) {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return s.issueRecoveryCodes(user.ID)
+}
+
+// VerifyLogin completes a login started with a password when the account has 2FA.
+// The code can be a TOTP code or an unused recovery code.
+func (s *twoFactorService) VerifyLogin(challengeToken, code string, client models.ClientInfo) (*LoginResult, error) {
+	claims, err := utils.ValidateChallengeToken(challengeToken, utils.PurposeTwoFactorChallenge)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	// Kode TOTP hanya 6 digit, jadi percobaan gagal ikut dihitung throttler login
+	if err := s.throttler.Check(claims.Username, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account is disabled")
+	}
+
+	if !user.TOTPEnabled {
+		return nil, ErrTwoFactorNotActive
+	}
+
+	if !s.verifyTOTP(user, code) {
+		consumed, err := s.recoveryRepo.Consume(user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			if err := s.throttler.RecordFailure(claims.Username, client.IPAddress, "invalid two-factor code"); err != nil {
+				return nil, err
+			}
+			return nil, ErrInvalidTwoFactorCode
+		}
+	}
+
+	s.throttler.RecordSuccess(claims.Username)
+
+	return s.authService.CompleteLogin(user, client)
+}
+
+func (s *twoFactorService) GetPolicy() (*TwoFactorPolicy, error) {
+	value, _, err := s.settingRepo.Get(SettingRequireTwoFactorSuperAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorPolicy{RequireForSuperAdmins: value == "true"}, nil
+}
+
+func (s *twoFactorService) SetRequireForSuperAdmins(require bool) error {
+	return s.settingRepo.Set(SettingRequireTwoFactorSuperAdmin, strconv.FormatBool(require))
+}
+
+// verifyTOTP checks the code and claims its time step so it cannot be replayed. The
+// claim is a conditional update, a concurrent request with the same code loses it.
+func (s *twoFactorService) verifyTOTP(user *models.User, code string) bool {
+	step, ok := utils.ValidateTOTPCode(user.TOTPSecret, code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return false
+	}
+
+	claimed, err := s.userRepo.ClaimTOTPStep(user.ID, step)
+	if err != nil || !claimed {
+		return false
+	}
+
+	user.TOTPLastStep = step
+	return true
+}
+
+func (s *twoFactorService) issueRecoveryCodes(userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"errors"
+	"haslaw-be-services/internal/models"
+	"haslaw-be-services/internal/repository"
+	"haslaw-be-services/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrLastSuperAdmin   = errors.New("cannot remove or demote the last active super admin")
+	ErrSelfModification = errors.New("you cannot disable, demote or delete your own account")
+)
+
+// UserService handles admin account lifecycle management (super admin only)
+type UserService interface {
+	ListAdmins(page, limit int, search string, role models.UserRole) ([]models.User, *utils.PaginationMeta, error)
+	GetAdmin(id uint) (*models.User, error)
+	UpdateAdmin(actorID, id uint, request *models.UpdateAdminRequest) (*models.User, error)
+	SetActive(actorID, id uint, active bool) (*models.User, error)
+	ChangeRole(actorID, id uint, role models.UserRole) (*models.User, error) // Target role must exist and not grant more than the actor holds, revokes the user's tokens
+	DeleteAdmin(actorID, id uint) error
+	CheckOutranks(actorID uint, target *models.User) error // ErrForbidden jika role target punya permission yang tidak dimiliki actor
+}
+
+type userService struct {
+	userRepo    repository.UserRepository
+	roleService RoleService
+	authService AuthService
+}
+
+func NewUserService(userRepo repository.UserRepository, roleService RoleService, authService AuthService) UserService {
+	return &userService{
+		userRepo:    userRepo,
+		roleService: roleService,
+		authService: authService,
+	}
+}
+
+func (s *userService) ListAdmins(page, limit int, search string, role models.UserRole) ([]models.User, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	users, total, err := s.userRepo.List(limit, offset, search, role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return users, meta, nil
+}
+
+func (s *userService) GetAdmin(id uint) (*models.User, error) {
+	return s.getUser(id)
+}
+
+func (s *userService) UpdateAdmin(actorID, id uint, request *models.UpdateAdminRequest) (*models.User, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Email baru berarti reset password masuk ke actor, jadi hanya untuk akun yang tidak lebih kuat
+	if err := s.CheckOutranks(actorID, user); err != nil {
+		return nil, err
+	}
+
+	if user.Username != request.Username {
+		existingUser, err := s.userRepo.GetByUsername(request.Username)
+		if err == nil && existingUser.ID != id {
+			return nil, errors.New("username already exists")
+		}
+	}
+
+	if user.Email != request.Email {
+		existingUser, err := s.userRepo.GetByEmail(request.Email)
+		if err == nil && existingUser.ID != id {
+			return nil, errors.New("email already exists")
+		}
+	}
+
+	user.Username = request.Username
+	user.Email = request.Email
+
+	if err := s.userRepo.Update(user, "username", "email"); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) SetActive(actorID, id uint, active bool) (*models.User, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsActive == active {
+		return user, nil
+	}
+
+	if err := s.CheckOutranks(actorID, user); err != nil {
+		return nil, err
+	}
+
+	if !active {
+		if actorID == id {
+			return nil, ErrSelfModification
+		}
+		if err := s.ensureNotLastSuperAdmin(user); err != nil {
+			return nil, err
+		}
+	}
+
+	user.IsActive = active
+	if err := s.userRepo.Update(user, "is_active"); err != nil {
+		return nil, err
+	}
+
+	// Token lama tidak boleh hidup lagi saat akun diaktifkan kembali
+	if !active {
+		if err := s.authService.RevokeAllUserTokens(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (s *userService) ChangeRole(actorID, id uint, role models.UserRole) (*models.User, error) {
+	target, err := s.roleService.GetRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	if actorID == id {
+		return nil, ErrSelfModification
+	}
+
+	actor, err := s.getUser(actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Role lama dan role baru sama-sama tidak boleh melebihi permission actor
+	if err := s.roleService.CanGrant(actor.Role, target.Permissions); err != nil {
+		return nil, err
+	}
+	if err := s.CheckOutranks(actorID, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureNotLastSuperAdmin(user); err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	if err := s.userRepo.Update(user, "role"); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.RevokeAllUserTokens(id); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) DeleteAdmin(actorID, id uint) error {
+	user, err := s.getUser(id)
+	if err != nil {
+		return err
+	}
+
+	if actorID == id {
+		return ErrSelfModification
+	}
+
+	if err := s.CheckOutranks(actorID, user); err != nil {
+		return err
+	}
+
+	if err := s.ensureNotLastSuperAdmin(user); err != nil {
+		return err
+	}
+
+	return s.userRepo.Delete(id)
+}
+
+// CheckOutranks allows the actor to manage target only when the actor's role holds every
+// permission of the target's role, so users:manage alone cannot take over a super admin
+func (s *userService) CheckOutranks(actorID uint, target *models.User) error {
+	actor, err := s.getUser(actorID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrForbidden
+		}
+		return err
+	}
+
+	permissions, err := s.roleService.PermissionsFor(target.Role)
+	if err != nil {
+		return err
+	}
+
+	return s.roleService.CanGrant(actor.Role, permissions)
+}
+
+func (s *userService) getUser(id uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ensureNotLastSuperAdmin blocks removing the only remaining active super admin
+func (s *userService) ensureNotLastSuperAdmin(user *models.User) error {
+	if user.Role != models.SuperAdmin || !user.IsActive {
+		return nil
+	}
+
+	count, err := s.userRepo.CountActiveByRole(models.SuperAdmin)
+	if err != nil {
+		return err
+	}
+
+	if count <= 1 {
+		return ErrLastSuperAdmin
+	}
+
+	return nil
+}
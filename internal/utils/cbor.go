@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// cborMaxDepth limits nesting so a crafted attestation cannot exhaust the stack
+const cborMaxDepth = 16
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+// DecodeCBOR decodes a single CBOR data item (RFC 8949) and returns the bytes after it.
+// It covers what WebAuthn needs: integers (int64), byte strings ([]byte), text strings,
+// arrays ([]interface{}), maps (map[interface{}]interface{} with int64 or string keys),
+// booleans, null and floats. Tags are skipped and indefinite lengths are rejected.
+func DecodeCBOR(data []byte) (interface{}, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, errors.New("cbor: nesting too deep")
+	}
+	if len(data) == 0 {
+		return nil, nil, errCBORTruncated
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+
+	if major == 7 {
+		return decodeCBORSimple(data, info)
+	}
+
+	argument, rest, err := readCBORArgument(data, info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if argument > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(argument), rest, nil
+	case 1:
+		if argument > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(argument), rest, nil
+	case 2, 3:
+		if argument > uint64(len(rest)) {
+			return nil, nil, errCBORTruncated
+		}
+		value := rest[:argument]
+		if major == 3 {
+			return string(value), rest[argument:], nil
+		}
+		return append([]byte(nil), value...), rest[argument:], nil
+	case 4:
+		// Setiap elemen minimal 1 byte, jadi panjang yang lebih besar pasti tidak valid
+		if argument > uint64(len(rest)) {
+			return nil, nil, errCBORTruncated
+		}
+		items := make([]interface{}, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			var item interface{}
+			if item, rest, err = decodeCBORItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5:
+		if argument > uint64(len(rest))/2 {
+			return nil, nil, errCBORTruncated
+		}
+		items := make(map[interface{}]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			var key, value interface{}
+			if key, rest, err = decodeCBORItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			if value, rest, err = decodeCBORItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			if _, exists := items[key]; exists {
+				return nil, nil, errors.New("cbor: duplicate map key")
+			}
+			items[key] = value
+		}
+		return items, rest, nil
+	case 6:
+		return decodeCBORItem(rest, depth+1)
+	}
+
+	return nil, nil, fmt.Errorf("cbor: unsupported major type %d", major)
+}
+
+func readCBORArgument(data []byte, info byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data[1:], nil
+	case info == 24:
+		if len(data) < 2 {
+			return 0, nil, errCBORTruncated
+		}
+		return uint64(data[1]), data[2:], nil
+	case info == 25:
+		if len(data) < 3 {
+			return 0, nil, errCBORTruncated
+		}
+		return uint64(binary.BigEndian.Uint16(data[1:3])), data[3:], nil
+	case info == 26:
+		if len(data) < 5 {
+			return 0, nil, errCBORTruncated
+		}
+		return uint64(binary.BigEndian.Uint32(data[1:5])), data[5:], nil
+	case info == 27:
+		if len(data) < 9 {
+			return 0, nil, errCBORTruncated
+		}
+		return binary.BigEndian.Uint64(data[1:9]), data[9:], nil
+	default:
+		return 0, nil, errors.New("cbor: indefinite length items are not supported")
+	}
+}
+
+func decodeCBORSimple(data []byte, info byte) (interface{}, []byte, error) {
+	switch info {
+	case 20:
+		return false, data[1:], nil
+	case 21:
+		return true, data[1:], nil
+	case 22, 23:
+		return nil, data[1:], nil
+	case 25:
+		if len(data) < 3 {
+			return nil, nil, errCBORTruncated
+		}
+		return float64(halfToFloat32(binary.BigEndian.Uint16(data[1:3]))), data[3:], nil
+	case 26:
+		if len(data) < 5 {
+			return nil, nil, errCBORTruncated
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:5]))), data[5:], nil
+	case 27:
+		if len(data) < 9 {
+			return nil, nil, errCBORTruncated
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data[1:9])), data[9:], nil
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
+
+func halfToFloat32(half uint16) float32 {
+	sign := uint32(half>>15) << 31
+	exponent := uint32(half>>10) & 0x1f
+	mantissa := uint32(half & 0x3ff)
+
+	switch exponent {
+	case 0:
+		// Subnormal: mantissa * 2^-24
+		value := float32(mantissa) / (1 << 24)
+		if sign != 0 {
+			value = -value
+		}
+		return value
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mantissa<<13)
+	default:
+		return math.Float32frombits(sign | (exponent+112)<<23 | mantissa<<13)
+	}
+}
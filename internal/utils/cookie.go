@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CSRFHeader carries the double-submit CSRF token on requests authenticated by the refresh cookie
+const CSRFHeader = "X-CSRF-Token"
+
+const (
+	refreshTokenCookie = "refresh_token"
+	csrfTokenCookie    = "csrf_token"
+	hostCookiePrefix   = "__Host-"
+)
+
+// ssoStateCookie holds the signed state of a pending SSO login between login and callback
+const (
+	ssoStateCookie     = "sso_state"
+	ssoStateCookiePath = "/api/v1/auth/sso"
+)
+
+// CookieConfig controls the attributes of the refresh token, CSRF and SSO state cookies
+type CookieConfig struct {
+	Secure     bool
+	SameSite   http.SameSite
+	Domain     string
+	Path       string // Scope of the refresh token cookie
+	HostPrefix bool   // Adds the __Host- prefix, which forces Secure, Path=/ and no Domain
+}
+
+var (
+	cookieConfig = CookieConfig{SameSite: http.SameSiteLaxMode, Path: "/api/v1/auth"}
+	cookieMutex  sync.RWMutex
+)
+
+// SetCookieConfig sets the attributes used by the refresh token and CSRF cookies.
+// Attributes the browser would reject are corrected: SameSite=None and the
+// __Host- prefix both require Secure, and the prefix also requires Path=/ without Domain.
+func SetCookieConfig(config CookieConfig) {
+	if config.Path == "" {
+		config.Path = "/"
+	}
+	if config.SameSite == http.SameSiteNoneMode {
+		config.Secure = true
+	}
+	if config.HostPrefix {
+		config.Secure = true
+		config.Domain = ""
+		config.Path = "/"
+	}
+
+	cookieMutex.Lock()
+	defer cookieMutex.Unlock()
+	cookieConfig = config
+}
+
+func currentCookieConfig() CookieConfig {
+	cookieMutex.RLock()
+	defer cookieMutex.RUnlock()
+	return cookieConfig
+}
+
+// ParseSameSite converts lax, strict or none to http.SameSite, defaulting to Lax
+func ParseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func cookieName(config CookieConfig, name string) string {
+	if config.HostPrefix {
+		return hostCookiePrefix + name
+	}
+	return name
+}
+
+// SetRefreshTokenCookie stores the refresh token in an httpOnly cookie together with a
+// fresh CSRF cookie, and returns the CSRF token the client must echo in CSRFHeader
+func SetRefreshTokenCookie(c *gin.Context, refreshToken string) (string, error) {
+	config := currentCookieConfig()
+
+	csrfToken, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName(config, refreshTokenCookie),
+		Value:    refreshToken,
+		Path:     config.Path,
+		Domain:   config.Domain,
+		MaxAge:   int(RefreshTokenTTL.Seconds()),
+		Secure:   config.Secure,
+		HttpOnly: true,
+		SameSite: config.SameSite,
+	})
+	setCSRFCookie(c, config, csrfToken, int(RefreshTokenTTL.Seconds()))
+
+	return csrfToken, nil
+}
+
+// ClearRefreshTokenCookie removes the refresh token and CSRF cookies
+func ClearRefreshTokenCookie(c *gin.Context) {
+	config := currentCookieConfig()
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName(config, refreshTokenCookie),
+		Path:     config.Path,
+		Domain:   config.Domain,
+		MaxAge:   -1,
+		Secure:   config.Secure,
+		HttpOnly: true,
+		SameSite: config.SameSite,
+	})
+	setCSRFCookie(c, config, "", -1)
+}
+
+// RefreshTokenFromCookie returns the refresh token sent by the browser
+func RefreshTokenFromCookie(c *gin.Context) (string, error) {
+	return c.Cookie(cookieName(currentCookieConfig(), refreshTokenCookie))
+}
+
+// CSRFTokenFromCookie returns the CSRF token cookie, or an empty string if there is none
+func CSRFTokenFromCookie(c *gin.Context) string {
+	token, _ := c.Cookie(cookieName(currentCookieConfig(), csrfTokenCookie))
+	return token
+}
+
+// IssueCSRFToken returns the current CSRF token, creating the cookie if it is missing
+func IssueCSRFToken(c *gin.Context) (string, error) {
+	if token := CSRFTokenFromCookie(c); token != "" {
+		return token, nil
+	}
+
+	token, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+	setCSRFCookie(c, currentCookieConfig(), token, int(RefreshTokenTTL.Seconds()))
+	return token, nil
+}
+
+// ValidCSRFToken reports whether the CSRF header matches the CSRF cookie
+func ValidCSRFToken(c *gin.Context) bool {
+	cookie := CSRFTokenFromCookie(c)
+	header := c.GetHeader(CSRFHeader)
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+// SetSSOStateCookie stores the signed state of a pending SSO login until the callback
+func SetSSOStateCookie(c *gin.Context, stateToken string, maxAge int) {
+	setSSOStateCookie(c, currentCookieConfig(), stateToken, maxAge)
+}
+
+// ClearSSOStateCookie removes the SSO state cookie once the callback has read it
+func ClearSSOStateCookie(c *gin.Context) {
+	setSSOStateCookie(c, currentCookieConfig(), "", -1)
+}
+
+// SSOStateFromCookie returns the SSO state token sent by the browser
+func SSOStateFromCookie(c *gin.Context) (string, error) {
+	return c.Cookie(cookieName(currentCookieConfig(), ssoStateCookie))
+}
+
+// setSSOStateCookie writes the SSO state cookie. The callback is a top-level redirect
+// from the identity provider, which a SameSite=Strict cookie would not be sent with,
+// so Strict is relaxed to Lax for this cookie only.
+func setSSOStateCookie(c *gin.Context, config CookieConfig, stateToken string, maxAge int) {
+	path := ssoStateCookiePath
+	if config.HostPrefix {
+		path = "/"
+	}
+	sameSite := config.SameSite
+	if sameSite == http.SameSiteStrictMode {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName(config, ssoStateCookie),
+		Value:    stateToken,
+		Path:     path,
+		Domain:   config.Domain,
+		MaxAge:   maxAge,
+		Secure:   config.Secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
+
+// setCSRFCookie writes the CSRF cookie. It is readable by scripts and scoped to the
+// whole site, so a frontend on the same site can copy it into CSRFHeader.
+func setCSRFCookie(c *gin.Context, config CookieConfig, token string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName(config, csrfTokenCookie),
+		Value:    token,
+		Path:     "/",
+		Domain:   config.Domain,
+		MaxAge:   maxAge,
+		Secure:   config.Secure,
+		HttpOnly: false,
+		SameSite: config.SameSite,
+	})
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"haslaw-be-services/internal/models"
+	"strings"
+)
+
+// Operations of a DiffLine
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells caps the size of the comparison table (lines of a times lines of b).
+// Larger texts are shown as a full replacement instead of a line-by-line diff.
+const maxDiffCells = 4_000_000
+
+// DiffLines compares two texts line by line and returns the lines of both in order,
+// marked as equal, deleted from a or inserted from b (longest common subsequence)
+func DiffLines(a, b string) []models.DiffLine {
+	if a == b {
+		if a == "" {
+			return []models.DiffLine{}
+		}
+		return diffAll(splitLines(a), DiffEqual, nil)
+	}
+
+	from, to := splitLines(a), splitLines(b)
+
+	// Baris yang sama di awal dan akhir tidak perlu masuk tabel
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	result := diffAll(from[:prefix], DiffEqual, nil)
+	middleFrom, middleTo := from[prefix:len(from)-suffix], to[prefix:len(to)-suffix]
+
+	if len(middleFrom)*len(middleTo) > maxDiffCells {
+		result = diffAll(middleFrom, DiffDelete, result)
+		result = diffAll(middleTo, DiffInsert, result)
+	} else {
+		result = diffMiddle(middleFrom, middleTo, result)
+	}
+
+	return diffAll(from[len(from)-suffix:], DiffEqual, result)
+}
+
+func diffMiddle(from, to []string, result []models.DiffLine) []models.DiffLine {
+	// lengths[i][j] is the longest common subsequence of from[i:] and to[j:]
+	lengths := make([][]int, len(from)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			result = append(result, models.DiffLine{Op: DiffEqual, Text: from[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			result = append(result, models.DiffLine{Op: DiffDelete, Text: from[i]})
+			i++
+		default:
+			result = append(result, models.DiffLine{Op: DiffInsert, Text: to[j]})
+			j++
+		}
+	}
+
+	result = diffAll(from[i:], DiffDelete, result)
+	return diffAll(to[j:], DiffInsert, result)
+}
+
+func diffAll(lines []string, op string, result []models.DiffLine) []models.DiffLine {
+	if result == nil {
+		result = make([]models.DiffLine, 0, len(lines))
+	}
+	for _, line := range lines {
+		result = append(result, models.DiffLine{Op: op, Text: line})
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -3,51 +3,129 @@ package utils
 import (
 	"errors"
 	"haslaw-be-services/internal/models"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Restrictions limit which routes a token may access until the user completes a required step
+const (
+	RestrictionTwoFactorSetup = "2fa_setup"
+	RestrictionPasswordChange = "password_change"
+)
+
+// Purposes mark short-lived tokens that are not access tokens
+const (
+	PurposeTwoFactorChallenge = "2fa_challenge"
+	PurposeInvitation         = "invitation"
+	PurposeOIDCState          = "oidc_state"
+	PurposePasskeyRegister    = "passkey_register"
+	PurposePasskeyLogin       = "passkey_login"
+)
+
+// Token types distinguish access tokens from refresh tokens of the same session
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Audiences separate the kinds of token signed with the same key, so a token of one
+// kind is never accepted where another is expected
+const (
+	AudienceAccess         = "haslaw:access"
+	AudienceRefresh        = "haslaw:refresh"
+	AudienceChallenge      = "haslaw:challenge"
+	AudienceInvitation     = "haslaw:invitation"
+	AudienceOIDCState      = "haslaw:oidc_state"
+	AudiencePasskeySession = "haslaw:passkey_session"
+)
+
+// Token lifetimes
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	TokenType   string `json:"typ,omitempty"`
+	SessionID   string `json:"sid,omitempty"` // Token family (satu sesi login)
+	Restriction string `json:"restriction,omitempty"`
+	Purpose     string `json:"purpose,omitempty"`
+	Version     uint   `json:"ver"` // Harus sama dengan User.TokenVersion
+
+	// Terisi jika token dipakai super admin untuk menyamar sebagai user ini
+	ImpersonatorID       uint   `json:"imp_id,omitempty"`
+	ImpersonatorUsername string `json:"imp_username,omitempty"`
+	AllowDestructive     bool   `json:"imp_destructive,omitempty"` // Impersonation boleh menjalankan operasi DELETE
 	jwt.RegisteredClaims
 }
 
-func GenerateTokens(userID uint, username string, role models.UserRole) (string, string, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+// IsImpersonation reports whether the token was issued to a super admin acting as the user
+func (c *Claims) IsImpersonation() bool {
+	return c.ImpersonatorID != 0
+}
+
+// TokenOptions carries optional claims embedded in issued tokens
+type TokenOptions struct {
+	SessionID      string
+	RefreshTokenID string // jti of the refresh token, tracked per session for reuse detection
+	Restriction    string
+	TokenVersion   uint
+}
+
+func GenerateTokens(userID uint, username string, role models.UserRole, opts TokenOptions) (string, string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", "", err
+	}
+
+	accessTokenID, err := GenerateSecureToken(16)
+	if err != nil {
+		return "", "", err
+	}
 
 	accessClaims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     string(role),
+		UserID:      userID,
+		Username:    username,
+		Role:        string(role),
+		TokenType:   TokenTypeAccess,
+		SessionID:   opts.SessionID,
+		Restriction: opts.Restriction,
+		Version:     opts.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ID:        accessTokenID,
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(jwtSecret)
+	accessTokenString, err := ring.Sign(accessClaims)
 	if err != nil {
 		return "", "", err
 	}
 
 	refreshClaims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     string(role),
+		UserID:      userID,
+		Username:    username,
+		Role:        string(role),
+		TokenType:   TokenTypeRefresh,
+		SessionID:   opts.SessionID,
+		Restriction: opts.Restriction,
+		Version:     opts.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+			ID:        opts.RefreshTokenID,
+			Audience:  jwt.ClaimStrings{AudienceRefresh},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(jwtSecret)
+	refreshTokenString, err := ring.Sign(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -55,13 +133,64 @@ func GenerateTokens(userID uint, username string, role models.UserRole) (string,
 	return accessTokenString, refreshTokenString, nil
 }
 
+// ImpersonationOptions names the super admin behind an impersonation token
+type ImpersonationOptions struct {
+	ImpersonatorID       uint
+	ImpersonatorUsername string
+	AllowDestructive     bool
+}
+
+// GenerateImpersonationToken issues an access token for the subject that also carries the
+// impersonator. There is no refresh token, the session simply ends after ttl.
+func GenerateImpersonationToken(userID uint, username string, role models.UserRole, opts TokenOptions, impersonation ImpersonationOptions, ttl time.Duration) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	tokenID, err := GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		UserID:               userID,
+		Username:             username,
+		Role:                 string(role),
+		TokenType:            TokenTypeAccess,
+		SessionID:            opts.SessionID,
+		Version:              opts.TokenVersion,
+		ImpersonatorID:       impersonation.ImpersonatorID,
+		ImpersonatorUsername: impersonation.ImpersonatorUsername,
+		AllowDestructive:     impersonation.AllowDestructive,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return ring.Sign(claims)
+}
+
+// ValidateToken validates an access token issued by GenerateTokens or GenerateImpersonationToken
 func ValidateToken(tokenString string) (*Claims, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+	return validateClaims(tokenString, AudienceAccess)
+}
+
+// ValidateRefreshToken validates a refresh token issued by GenerateTokens
+func ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return validateClaims(tokenString, AudienceRefresh)
+}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+func validateClaims(tokenString, audience string) (*Claims, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
 
+	token, err := ring.Parse(tokenString, &Claims{}, audience)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +202,190 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// GenerateChallengeToken issues a short-lived token for an intermediate step such as a 2FA challenge
+func GenerateChallengeToken(userID uint, username, purpose string, ttl time.Duration) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		UserID:   userID,
+		Username: username,
+		Purpose:  purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceChallenge},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return ring.Sign(claims)
+}
+
+// GenerateInvitationToken issues a signed invite token with the invited email as subject,
+// so the frontend can prefill the form. The random jti gives every resend a different token.
+func GenerateInvitationToken(email string, ttl time.Duration) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	tokenID, err := GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		Purpose: PurposeInvitation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   email,
+			Audience:  jwt.ClaimStrings{AudienceInvitation},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return ring.Sign(claims)
+}
+
+// OIDCStateClaims keeps the state, nonce and PKCE verifier of a pending SSO login
+// in a signed cookie, so any API instance can handle the callback
+type OIDCStateClaims struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Purpose  string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GenerateOIDCStateToken signs the state of a pending SSO login
+func GenerateOIDCStateToken(state, nonce, verifier string, ttl time.Duration) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &OIDCStateClaims{
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		Purpose:  PurposeOIDCState,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceOIDCState},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return ring.Sign(claims)
+}
+
+// ValidateOIDCStateToken validates a token issued by GenerateOIDCStateToken
+func ValidateOIDCStateToken(tokenString string) (*OIDCStateClaims, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &OIDCStateClaims{}
+	token, err := ring.Parse(tokenString, claims, AudienceOIDCState)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || claims.Purpose != PurposeOIDCState {
+		return nil, errors.New("invalid state token")
+	}
+
+	return claims, nil
+}
+
+// PasskeySessionClaims carries the WebAuthn challenge between the begin and finish
+// steps. UserID is 0 for a passwordless login, where the user is only known afterwards.
+type PasskeySessionClaims struct {
+	UserID    uint   `json:"user_id"`
+	Challenge string `json:"challenge"`
+	Purpose   string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePasskeySessionToken signs a WebAuthn challenge. The jti lets the session be used only once.
+func GeneratePasskeySessionToken(userID uint, challenge, purpose string, ttl time.Duration) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	tokenID, err := GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &PasskeySessionClaims{
+		UserID:    userID,
+		Challenge: challenge,
+		Purpose:   purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Audience:  jwt.ClaimStrings{AudiencePasskeySession},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return ring.Sign(claims)
+}
+
+// ValidatePasskeySessionToken validates a token issued by GeneratePasskeySessionToken for the given purpose
+func ValidatePasskeySessionToken(tokenString, purpose string) (*PasskeySessionClaims, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &PasskeySessionClaims{}
+	token, err := ring.Parse(tokenString, claims, AudiencePasskeySession)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || claims.Purpose != purpose {
+		return nil, errors.New("invalid passkey session")
+	}
+
+	return claims, nil
+}
+
+// ValidateChallengeToken validates a token issued by GenerateChallengeToken for the given purpose
+func ValidateChallengeToken(tokenString, purpose string) (*Claims, error) {
+	claims, err := validateClaims(tokenString, AudienceChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != purpose {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
+// ValidateInvitationToken validates a token issued by GenerateInvitationToken
+func ValidateInvitationToken(tokenString string) (*Claims, error) {
+	claims, err := validateClaims(tokenString, AudienceInvitation)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != PurposeInvitation {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	return claims, nil
+}
+
 func HashPassword(password string) (string, error) {
 	cost := 12
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
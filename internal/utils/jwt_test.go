@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"haslaw-be-services/internal/models"
+	"testing"
+	"time"
+)
+
+func TestTokenKindsAreNotInterchangeable(t *testing.T) {
+	ring, err := NewKeyRing(KeyRingConfig{Algorithm: AlgorithmHS256, Secret: "jwt-test-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKeyRing(ring)
+	t.Cleanup(func() { SetKeyRing(nil) })
+
+	access, refresh, err := GenerateTokens(1, "admin", models.SuperAdmin, TokenOptions{SessionID: "family", RefreshTokenID: "refresh-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := GenerateChallengeToken(1, "admin", PurposeTwoFactorChallenge, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invitation, err := GenerateInvitationToken("new@haslaw.test", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oidcState, err := GenerateOIDCStateToken("state", "nonce", "verifier", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkeySession, err := GeneratePasskeySessionToken(1, "challenge", PurposePasskeyLogin, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := map[string]string{
+		"access":          access,
+		"refresh":         refresh,
+		"challenge":       challenge,
+		"invitation":      invitation,
+		"oidc state":      oidcState,
+		"passkey session": passkeySession,
+	}
+	validators := map[string]func(string) error{
+		"access": func(token string) error {
+			_, err := ValidateToken(token)
+			return err
+		},
+		"refresh": func(token string) error {
+			_, err := ValidateRefreshToken(token)
+			return err
+		},
+		"challenge": func(token string) error {
+			_, err := ValidateChallengeToken(token, PurposeTwoFactorChallenge)
+			return err
+		},
+		"invitation": func(token string) error {
+			_, err := ValidateInvitationToken(token)
+			return err
+		},
+		"oidc state": func(token string) error {
+			_, err := ValidateOIDCStateToken(token)
+			return err
+		},
+		"passkey session": func(token string) error {
+			_, err := ValidatePasskeySessionToken(token, PurposePasskeyLogin)
+			return err
+		},
+	}
+
+	for tokenKind, token := range tokens {
+		for validatorKind, validate := range validators {
+			err := validate(token)
+			if tokenKind == validatorKind && err != nil {
+				t.Errorf("%s token rejected by its own validator: %v", tokenKind, err)
+			}
+			if tokenKind != validatorKind && err == nil {
+				t.Errorf("%s token accepted as %s token", tokenKind, validatorKind)
+			}
+		}
+	}
+}
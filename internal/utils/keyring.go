@@ -0,0 +1,337 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// legacyKeyID is used for HS256 tokens, which never carry a published key
+const legacyKeyID = "hs256"
+
+// KeyRingConfig describes where signing keys come from
+type KeyRingConfig struct {
+	Algorithm   string // HS256, RS256 atau EdDSA
+	Secret      string // Hanya dipakai untuk HS256
+	KeysDir     string // Folder berisi <kid>.pem (private key) atau <kid>.pub.pem (public key saja)
+	ActiveKeyID string // kid yang dipakai untuk menandatangani token baru
+}
+
+// JWTKey is one key of the ring. Keys without a private part can only verify.
+type JWTKey struct {
+	ID        string
+	Algorithm string
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// CanSign reports whether the key holds private material
+func (k *JWTKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeyRing holds the active signing key and every key that is still accepted for verification
+type KeyRing struct {
+	config  KeyRingConfig
+	mutex   sync.RWMutex
+	signing *JWTKey
+	keys    map[string]*JWTKey
+}
+
+// JWK is a single public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	defaultKeyRing *KeyRing
+	keyRingMutex   sync.RWMutex
+)
+
+// NewKeyRing loads the keys described by config
+func NewKeyRing(config KeyRingConfig) (*KeyRing, error) {
+	ring := &KeyRing{config: config}
+	if err := ring.Reload(); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// SetKeyRing makes ring the key ring used by GenerateTokens and ValidateToken
+func SetKeyRing(ring *KeyRing) {
+	keyRingMutex.Lock()
+	defer keyRingMutex.Unlock()
+	defaultKeyRing = ring
+}
+
+// currentKeyRing returns the configured key ring, falling back to HS256 with JWT_SECRET
+// for tools that never call SetKeyRing
+func currentKeyRing() (*KeyRing, error) {
+	keyRingMutex.RLock()
+	ring := defaultKeyRing
+	keyRingMutex.RUnlock()
+	if ring != nil {
+		return ring, nil
+	}
+
+	ring, err := NewKeyRing(KeyRingConfig{Algorithm: AlgorithmHS256, Secret: os.Getenv("JWT_SECRET")})
+	if err != nil {
+		return nil, err
+	}
+	SetKeyRing(ring)
+	return ring, nil
+}
+
+// Reload re-reads the key directory. Rotating keys is done by adding the new key file,
+// pointing JWT_ACTIVE_KEY_ID at it and keeping the old public key until its tokens expire.
+func (r *KeyRing) Reload() error {
+	keys := make(map[string]*JWTKey)
+
+	var signing *JWTKey
+	switch r.config.Algorithm {
+	case "", AlgorithmHS256:
+		if r.config.Secret == "" {
+			return errors.New("JWT_SECRET is required for HS256")
+		}
+		// Secret tidak ikut diterima saat memakai RS256/EdDSA, jadi token HS256 lama harus login ulang
+		signing = &JWTKey{
+			ID:        legacyKeyID,
+			Algorithm: AlgorithmHS256,
+			signKey:   []byte(r.config.Secret),
+			verifyKey: []byte(r.config.Secret),
+		}
+		keys[legacyKeyID] = signing
+	case AlgorithmRS256, AlgorithmEdDSA:
+		loaded, err := loadKeyDir(r.config.KeysDir)
+		if err != nil {
+			return err
+		}
+		for id, key := range loaded {
+			if key.Algorithm != r.config.Algorithm {
+				return fmt.Errorf("key %s is %s, expected %s", id, key.Algorithm, r.config.Algorithm)
+			}
+			keys[id] = key
+		}
+
+		activeID := r.config.ActiveKeyID
+		if activeID == "" {
+			activeID = newestSigningKey(loaded)
+		}
+		signing = loaded[activeID]
+		if signing == nil || !signing.CanSign() {
+			return fmt.Errorf("no private key found for active key id %q in %s", activeID, r.config.KeysDir)
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", r.config.Algorithm)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.signing = signing
+	r.keys = keys
+
+	return nil
+}
+
+// Sign signs claims with the active key and sets the kid header
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	r.mutex.RLock()
+	key := r.signing
+	r.mutex.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	if key.ID != legacyKeyID {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.signKey)
+}
+
+// Parse verifies a token against the key named by its kid header and requires its aud
+// claim to contain audience. Tokens without a kid are only accepted when the ring uses HS256.
+func (r *KeyRing) Parse(tokenString string, claims jwt.Claims, audience string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = legacyKeyID
+		}
+
+		r.mutex.RLock()
+		key := r.keys[kid]
+		r.mutex.RUnlock()
+
+		if key == nil {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		// Cegah algorithm confusion: alg di header harus sama dengan alg kunci
+		if token.Method.Alg() != key.Algorithm {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
+	}, jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmRS256, AlgorithmEdDSA}), jwt.WithAudience(audience))
+}
+
+// JWKS returns the public keys of the ring. HS256 secrets are never published.
+func (r *KeyRing) JWKS() JWKSet {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(r.keys))}
+	for _, key := range r.keys {
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// loadKeyDir reads <kid>.pem private keys and <kid>.pub.pem public keys from dir
+func loadKeyDir(dir string) (map[string]*JWTKey, error) {
+	if dir == "" {
+		return nil, errors.New("JWT_KEYS_DIR is required for asymmetric signing")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*JWTKey)
+	for _, file := range files {
+		name := filepath.Base(file)
+		id := strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parsePEMKey(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		// Private key menang jika kid yang sama punya file .pem dan .pub.pem
+		if existing, ok := keys[id]; ok && existing.CanSign() {
+			continue
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+func parsePEMKey(id string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY":
+		var parsed interface{}
+		var err error
+		if block.Type == "RSA PRIVATE KEY" {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		algorithm, err := algorithmFor(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		return &JWTKey{ID: id, Algorithm: algorithm, signKey: signer, verifyKey: signer.Public()}, nil
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		algorithm, err := algorithmFor(public)
+		if err != nil {
+			return nil, err
+		}
+		return &JWTKey{ID: id, Algorithm: algorithm, verifyKey: public}, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func algorithmFor(public crypto.PublicKey) (string, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", errors.New("only RSA and Ed25519 keys are supported")
+	}
+}
+
+// newestSigningKey picks the last private key by kid, so date-based kids like 2025-01 work without configuration
+func newestSigningKey(keys map[string]*JWTKey) string {
+	newest := ""
+	for id, key := range keys {
+		if key.CanSign() && id > newest {
+			newest = id
+		}
+	}
+	return newest
+}
@@ -0,0 +1,402 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcMaxResponseSize caps discovery, JWKS and token responses read from the provider
+const oidcMaxResponseSize = 1 << 20
+
+// oidcKeyRefreshInterval limits how often an unknown kid triggers a JWKS refetch
+const oidcKeyRefreshInterval = time.Minute
+
+// OIDCConfig describes the relying party registration at the identity provider
+type OIDCConfig struct {
+	Issuer       string // Tanpa /.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string // Kosong untuk public client (hanya PKCE)
+	RedirectURL  string // Callback API ini, harus terdaftar di provider
+	Scopes       []string
+	HTTPTimeout  time.Duration
+}
+
+// IDTokenClaims are the ID token claims used to find or provision the local user
+type IDTokenClaims struct {
+	Email             string   `json:"email"`
+	EmailVerified     oidcBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Nonce             string   `json:"nonce"`
+	AuthorizedParty   string   `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// oidcBool accepts both true and "true", some providers send email_verified as a string
+type oidcBool bool
+
+func (b *oidcBool) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	*b = oidcBool(strings.EqualFold(value, "true"))
+	return nil
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCProvider is a minimal OpenID Connect relying party: discovery, authorization
+// code flow with PKCE and ID token validation. Discovery runs on first use, so the
+// API starts even when the provider is down.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mutex         sync.RWMutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider creates a new OIDC provider client
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.HTTPTimeout <= 0 {
+		config.HTTPTimeout = 10 * time.Second
+	}
+	return &OIDCProvider{
+		config: config,
+		client: &http.Client{Timeout: config.HTTPTimeout},
+	}
+}
+
+// NewPKCE returns a code verifier and its S256 code challenge
+func NewPKCE() (verifier, challenge string, err error) {
+	verifier, err = GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// AuthCodeURL builds the authorization request the browser is redirected to
+func (p *OIDCProvider) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange redeems the authorization code and returns the raw ID token
+func (p *OIDCProvider) Exchange(code, codeVerifier string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// client_secret_basic, nilai harus di-encode dulu (RFC 6749 2.3.1)
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var token oidcTokenResponse
+	status, err := p.do(request, &token)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK || token.Error != "" {
+		return "", fmt.Errorf("token request failed (%d): %s %s", status, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+
+	return token.IDToken, nil
+}
+
+// VerifyIDToken checks the signature against the provider JWKS, the issuer,
+// audience, expiry and the nonce sent in the authorization request
+func (p *OIDCProvider) VerifyIDToken(rawIDToken, nonce string) (*IDTokenClaims, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(discovery, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("invalid ID token: missing sub")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("invalid ID token: azp does not match the client")
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("invalid ID token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// Issuer returns the configured issuer, used to scope linked identities
+func (p *OIDCProvider) Issuer() string {
+	return p.config.Issuer
+}
+
+func (p *OIDCProvider) discover() (*oidcDiscovery, error) {
+	p.mutex.RLock()
+	discovery := p.discovery
+	p.mutex.RUnlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, strings.TrimRight(p.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery = &oidcDiscovery{}
+	status, err := p.do(request, discovery)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery failed with status %d", status)
+	}
+
+	// Issuer di dokumen harus sama dengan yang dikonfigurasi (OIDC Discovery 4.3)
+	if strings.TrimRight(discovery.Issuer, "/") != strings.TrimRight(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", discovery.Issuer, p.config.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing endpoints")
+	}
+
+	p.mutex.Lock()
+	p.discovery = discovery
+	p.mutex.Unlock()
+
+	return discovery, nil
+}
+
+// key returns the verification key for kid, refetching the JWKS when the provider rotated its keys
+func (p *OIDCProvider) key(discovery *oidcDiscovery, kid string) (crypto.PublicKey, error) {
+	if key := p.cachedKey(kid); key != nil {
+		return key, nil
+	}
+
+	p.mutex.RLock()
+	recentlyFetched := time.Since(p.keysFetchedAt) < oidcKeyRefreshInterval
+	p.mutex.RUnlock()
+	if !recentlyFetched {
+		if err := p.fetchKeys(discovery); err != nil {
+			return nil, err
+		}
+		if key := p.cachedKey(kid); key != nil {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// cachedKey looks up kid. Tokens without kid are accepted only when the provider publishes a single key.
+func (p *OIDCProvider) cachedKey(kid string) crypto.PublicKey {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return p.keys[kid]
+}
+
+func (p *OIDCProvider) fetchKeys(discovery *oidcDiscovery) error {
+	request, err := http.NewRequest(http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+
+	var set JWKSet
+	status, err := p.do(request, &set)
+	if err != nil {
+		return fmt.Errorf("failed to fetch provider keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to fetch provider keys: status %d", status)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			// Kunci yang tidak didukung dilewati, provider boleh mempublikasikan jenis lain
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	p.mutex.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	p.mutex.Unlock()
+
+	return nil
+}
+
+func (p *OIDCProvider) do(request *http.Request, target interface{}) (int, error) {
+	response, err := p.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, oidcMaxResponseSize))
+	if err != nil {
+		return response.StatusCode, err
+	}
+	if err := json.Unmarshal(body, target); err != nil && response.StatusCode == http.StatusOK {
+		return response.StatusCode, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	return response.StatusCode, nil
+}
+
+// PublicKey decodes an RSA, EC or Ed25519 JWK
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return key, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// SearchTerms splits a search query into lowercase words without duplicates. Single
+// characters are left out, the full-text index does not hold them either.
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// PlainText strips HTML tags and entities and collapses whitespace
+func PlainText(text string) string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Highlight escapes text for HTML and wraps every occurrence of the terms in <mark>
+func Highlight(text string, terms []string) string {
+	pattern := termPattern(terms)
+	if pattern == nil {
+		return html.EscapeString(text)
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		builder.WriteString(html.EscapeString(text[last:match[0]]))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		builder.WriteString("</mark>")
+		last = match[1]
+	}
+	builder.WriteString(html.EscapeString(text[last:]))
+
+	return builder.String()
+}
+
+// Snippet cuts about width characters of the plain text around the first match and
+// highlights the terms in it. Without a match it is the start of the text.
+func Snippet(text string, terms []string, width int) string {
+	text = PlainText(text)
+	runes := []rune(text)
+
+	start := 0
+	if pattern := termPattern(terms); pattern != nil {
+		if match := pattern.FindStringIndex(text); match != nil {
+			// Sepertiga potongan sebelum kata yang cocok, sisanya sesudahnya
+			start = max(utf8.RuneCountInString(text[:match[0]])-width/3, 0)
+		}
+	}
+	end := min(start+width, len(runes))
+	start = max(min(start, end-width), 0)
+
+	// Potong di spasi agar kata tidak terbelah, kecuali potongannya satu kata panjang
+	from, to := start, end
+	if from > 0 {
+		for from < to && !unicode.IsSpace(runes[from-1]) {
+			from++
+		}
+	}
+	if to < len(runes) {
+		for to > from && !unicode.IsSpace(runes[to]) {
+			to--
+		}
+	}
+	if from >= to {
+		from, to = start, end
+	}
+
+	snippet := Highlight(strings.TrimSpace(string(runes[from:to])), terms)
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// termPattern matches any of the terms case-insensitively, longest first
+func termPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+
+	return regexp.MustCompile(`(?i)(` + strings.Join(quoted, "|") + `)`)
+}
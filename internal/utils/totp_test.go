@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	const charset = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	codes, err := GenerateRecoveryCodes(2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[rune]int)
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+
+		for _, r := range NormalizeRecoveryCode(code) {
+			if !strings.ContainsRune(charset, r) {
+				t.Fatalf("code %q has character %q outside the charset", code, r)
+			}
+			counts[r]++
+		}
+	}
+
+	// 20000 karakter, rata-rata ~645 per karakter; batas ini jauh di luar variasi acak
+	expected := float64(len(codes)*10) / float64(len(charset))
+	for _, r := range charset {
+		if count := float64(counts[r]); count < expected*0.75 || count > expected*1.25 {
+			t.Errorf("character %q appeared %v times, expected about %.0f", r, count, expected)
+		}
+	}
+}
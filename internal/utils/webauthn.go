@@ -0,0 +1,472 @@
+package utils
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// COSE algorithm identifiers accepted for passkeys
+const (
+	COSEAlgES256 int64 = -7
+	COSEAlgEdDSA int64 = -8
+	COSEAlgES384 int64 = -35
+	COSEAlgES512 int64 = -36
+	COSEAlgPS256 int64 = -37
+	COSEAlgRS256 int64 = -257
+)
+
+// SupportedCOSEAlgorithms is offered as pubKeyCredParams, in order of preference
+var SupportedCOSEAlgorithms = []int64{COSEAlgES256, COSEAlgEdDSA, COSEAlgRS256, COSEAlgPS256, COSEAlgES384, COSEAlgES512}
+
+// Authenticator data flags (WebAuthn 6.1)
+const (
+	authenticatorFlagUserPresent  = 0x01
+	authenticatorFlagUserVerified = 0x04
+	authenticatorFlagAttested     = 0x40
+	authenticatorFlagExtensions   = 0x80
+)
+
+// Client data types
+const (
+	WebAuthnTypeCreate = "webauthn.create"
+	WebAuthnTypeGet    = "webauthn.get"
+)
+
+var ErrWebAuthnVerification = errors.New("passkey verification failed")
+
+// WebAuthnConfig identifies the relying party
+type WebAuthnConfig struct {
+	RPID    string   // Domain, mis. haslaw.com
+	RPName  string   // Nama yang tampil di dialog passkey
+	Origins []string // Origin frontend yang boleh memakai passkey, mis. https://admin.haslaw.com
+}
+
+// AuthenticatorData is the parsed authenticatorData structure
+type AuthenticatorData struct {
+	RPIDHash            []byte
+	Flags               byte
+	SignCount           uint32
+	AAGUID              []byte
+	CredentialID        []byte
+	CredentialPublicKey []byte // COSE_Key, hanya saat registrasi
+}
+
+func (a *AuthenticatorData) UserPresent() bool  { return a.Flags&authenticatorFlagUserPresent != 0 }
+func (a *AuthenticatorData) UserVerified() bool { return a.Flags&authenticatorFlagUserVerified != 0 }
+
+// CollectedClientData is clientDataJSON
+type CollectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// RegisteredCredential is the result of a verified registration
+type RegisteredCredential struct {
+	ID                []byte
+	PublicKey         []byte // COSE_Key
+	Algorithm         int64
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	UserVerified      bool
+}
+
+// VerifyRegistration checks a navigator.credentials.create() response: client data,
+// RP ID hash, flags, the credential public key and the attestation statement
+// (none, packed and fido-u2f). Attestation certificates are checked for a valid
+// signature but not against a list of trusted roots.
+func VerifyRegistration(config WebAuthnConfig, challenge string, clientDataJSON, attestationObject []byte, requireUserVerification bool) (*RegisteredCredential, error) {
+	if err := verifyClientData(config, clientDataJSON, WebAuthnTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, rest, err := DecodeCBOR(attestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, webAuthnError("invalid attestation object")
+	}
+	attestation, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, webAuthnError("invalid attestation object")
+	}
+	format, _ := attestation["fmt"].(string)
+	rawAuthData, _ := attestation["authData"].([]byte)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	if format == "" || rawAuthData == nil || statement == nil {
+		return nil, webAuthnError("attestation object is missing fields")
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAuthenticatorData(config, authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+	if authData.CredentialPublicKey == nil {
+		return nil, webAuthnError("no attested credential data")
+	}
+
+	publicKey, algorithm, err := ParseCOSEKey(authData.CredentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifyAttestationStatement(format, statement, rawAuthData, clientDataHash[:], authData, publicKey, algorithm); err != nil {
+		return nil, err
+	}
+
+	return &RegisteredCredential{
+		ID:                authData.CredentialID,
+		PublicKey:         authData.CredentialPublicKey,
+		Algorithm:         algorithm,
+		SignCount:         authData.SignCount,
+		AAGUID:            authData.AAGUID,
+		AttestationFormat: format,
+		UserVerified:      authData.UserVerified(),
+	}, nil
+}
+
+// VerifyAssertion checks a navigator.credentials.get() response against the stored
+// COSE public key and sign counter, and returns the new sign counter
+func VerifyAssertion(config WebAuthnConfig, challenge string, clientDataJSON, rawAuthData, signature, cosePublicKey []byte, storedSignCount uint32, requireUserVerification bool) (uint32, error) {
+	if err := verifyClientData(config, clientDataJSON, WebAuthnTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkAuthenticatorData(config, authData, requireUserVerification); err != nil {
+		return 0, err
+	}
+
+	publicKey, algorithm, err := ParseCOSEKey(cosePublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if err := verifyCOSESignature(publicKey, algorithm, signed, signature); err != nil {
+		return 0, err
+	}
+
+	// Counter yang tidak naik menandakan authenticator mungkin di-clone.
+	// Passkey yang disinkronkan selalu mengirim 0, jadi 0 dan 0 tetap diterima.
+	if (authData.SignCount != 0 || storedSignCount != 0) && authData.SignCount <= storedSignCount {
+		return 0, webAuthnError("sign counter did not increase, the authenticator may be cloned")
+	}
+
+	return authData.SignCount, nil
+}
+
+// ParseAuthenticatorData parses rpIdHash, flags, signCount and the attested credential data
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, webAuthnError("authenticator data too short")
+	}
+
+	authData := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if authData.Flags&authenticatorFlagAttested != 0 {
+		if len(rest) < 18 {
+			return nil, webAuthnError("attested credential data too short")
+		}
+		authData.AAGUID = rest[:16]
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength > 1023 || len(rest) < idLength {
+			return nil, webAuthnError("invalid credential ID length")
+		}
+		authData.CredentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		_, afterKey, err := DecodeCBOR(rest)
+		if err != nil {
+			return nil, webAuthnError("invalid credential public key")
+		}
+		authData.CredentialPublicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if authData.Flags&authenticatorFlagExtensions != 0 {
+		_, afterExtensions, err := DecodeCBOR(rest)
+		if err != nil {
+			return nil, webAuthnError("invalid extension data")
+		}
+		rest = afterExtensions
+	}
+
+	if len(rest) != 0 {
+		return nil, webAuthnError("unexpected trailing authenticator data")
+	}
+
+	return authData, nil
+}
+
+// ParseCOSEKey decodes an EC2, RSA or OKP COSE_Key and returns the key with its algorithm
+func ParseCOSEKey(raw []byte) (crypto.PublicKey, int64, error) {
+	decoded, rest, err := DecodeCBOR(raw)
+	if err != nil || len(rest) != 0 {
+		return nil, 0, webAuthnError("invalid COSE key")
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, webAuthnError("invalid COSE key")
+	}
+
+	keyType, _ := key[int64(1)].(int64)
+	algorithm, _ := key[int64(3)].(int64)
+
+	switch keyType {
+	case 2: // EC2
+		curveID, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		curves := map[int64]struct {
+			curve     elliptic.Curve
+			algorithm int64
+		}{
+			1: {elliptic.P256(), COSEAlgES256},
+			2: {elliptic.P384(), COSEAlgES384},
+			3: {elliptic.P521(), COSEAlgES512},
+		}
+		params, supported := curves[curveID]
+		if !supported || algorithm != params.algorithm {
+			return nil, 0, webAuthnError("unsupported EC key")
+		}
+		publicKey := &ecdsa.PublicKey{Curve: params.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if x == nil || y == nil || !params.curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, 0, webAuthnError("invalid EC point")
+		}
+		return publicKey, algorithm, nil
+	case 3: // RSA
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if algorithm != COSEAlgRS256 && algorithm != COSEAlgPS256 {
+			return nil, 0, webAuthnError("unsupported RSA algorithm")
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if n == nil || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, 0, webAuthnError("invalid RSA key")
+		}
+		publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+		if publicKey.N.BitLen() < 2048 {
+			return nil, 0, webAuthnError("RSA key too small")
+		}
+		return publicKey, algorithm, nil
+	case 1: // OKP
+		curveID, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if curveID != 6 || algorithm != COSEAlgEdDSA || len(x) != ed25519.PublicKeySize {
+			return nil, 0, webAuthnError("unsupported OKP key")
+		}
+		return ed25519.PublicKey(x), algorithm, nil
+	default:
+		return nil, 0, webAuthnError("unsupported COSE key type")
+	}
+}
+
+func verifyClientData(config WebAuthnConfig, clientDataJSON []byte, expectedType, challenge string) error {
+	var clientData CollectedClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return webAuthnError("invalid client data")
+	}
+
+	if clientData.Type != expectedType {
+		return webAuthnError("unexpected client data type")
+	}
+	if subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(challenge)) != 1 {
+		return webAuthnError("challenge mismatch")
+	}
+	if clientData.CrossOrigin {
+		return webAuthnError("cross-origin requests are not allowed")
+	}
+
+	for _, origin := range config.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+	return webAuthnError(fmt.Sprintf("origin %q is not allowed", clientData.Origin))
+}
+
+func checkAuthenticatorData(config WebAuthnConfig, authData *AuthenticatorData, requireUserVerification bool) error {
+	rpIDHash := sha256.Sum256([]byte(config.RPID))
+	if subtle.ConstantTimeCompare(authData.RPIDHash, rpIDHash[:]) != 1 {
+		return webAuthnError("RP ID hash mismatch")
+	}
+	if !authData.UserPresent() {
+		return webAuthnError("user presence is required")
+	}
+	if requireUserVerification && !authData.UserVerified() {
+		return webAuthnError("user verification is required")
+	}
+	return nil
+}
+
+func verifyAttestationStatement(format string, statement map[interface{}]interface{}, rawAuthData, clientDataHash []byte, authData *AuthenticatorData, credentialKey crypto.PublicKey, credentialAlgorithm int64) error {
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash...)
+
+	switch format {
+	case "none":
+		if len(statement) != 0 {
+			return webAuthnError("none attestation must have an empty statement")
+		}
+		return nil
+
+	case "packed":
+		algorithm, _ := statement["alg"].(int64)
+		signature, _ := statement["sig"].([]byte)
+		if signature == nil {
+			return webAuthnError("packed attestation is missing sig")
+		}
+
+		certificate, err := attestationCertificate(statement)
+		if err != nil {
+			return err
+		}
+		if certificate == nil {
+			// Self attestation: ditandatangani dengan kunci credential itu sendiri
+			if algorithm != credentialAlgorithm {
+				return webAuthnError("self attestation algorithm mismatch")
+			}
+			return verifyCOSESignature(credentialKey, algorithm, signed, signature)
+		}
+
+		if certificate.Version != 3 || certificate.IsCA {
+			return webAuthnError("invalid attestation certificate")
+		}
+		return verifyCOSESignature(certificate.PublicKey, algorithm, signed, signature)
+
+	case "fido-u2f":
+		signature, _ := statement["sig"].([]byte)
+		certificate, err := attestationCertificate(statement)
+		if err != nil {
+			return err
+		}
+		if certificate == nil || signature == nil {
+			return webAuthnError("fido-u2f attestation is missing x5c or sig")
+		}
+		ecKey, ok := credentialKey.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return webAuthnError("fido-u2f requires a P-256 credential key")
+		}
+
+		var verificationData bytes.Buffer
+		verificationData.WriteByte(0x00)
+		verificationData.Write(authData.RPIDHash)
+		verificationData.Write(clientDataHash)
+		verificationData.Write(authData.CredentialID)
+		verificationData.WriteByte(0x04)
+		verificationData.Write(ecKey.X.FillBytes(make([]byte, 32)))
+		verificationData.Write(ecKey.Y.FillBytes(make([]byte, 32)))
+
+		return verifyCOSESignature(certificate.PublicKey, COSEAlgES256, verificationData.Bytes(), signature)
+
+	default:
+		return webAuthnError(fmt.Sprintf("unsupported attestation format %q", format))
+	}
+}
+
+// attestationCertificate returns the first x5c certificate, or nil when the statement has none
+func attestationCertificate(statement map[interface{}]interface{}) (*x509.Certificate, error) {
+	chain, ok := statement["x5c"].([]interface{})
+	if !ok || len(chain) == 0 {
+		return nil, nil
+	}
+	raw, ok := chain[0].([]byte)
+	if !ok {
+		return nil, webAuthnError("invalid x5c")
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, webAuthnError("invalid attestation certificate")
+	}
+	return certificate, nil
+}
+
+func verifyCOSESignature(publicKey crypto.PublicKey, algorithm int64, data, signature []byte) error {
+	valid := false
+
+	switch algorithm {
+	case COSEAlgES256, COSEAlgES384, COSEAlgES512:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			break
+		}
+		var digest []byte
+		switch algorithm {
+		case COSEAlgES256:
+			sum := sha256.Sum256(data)
+			digest = sum[:]
+		case COSEAlgES384:
+			sum := sha512.Sum384(data)
+			digest = sum[:]
+		default:
+			sum := sha512.Sum512(data)
+			digest = sum[:]
+		}
+		valid = ecdsa.VerifyASN1(key, digest, signature)
+	case COSEAlgRS256, COSEAlgPS256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			break
+		}
+		digest := sha256.Sum256(data)
+		if algorithm == COSEAlgRS256 {
+			valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+		} else {
+			valid = rsa.VerifyPSS(key, crypto.SHA256, digest[:], signature, nil) == nil
+		}
+	case COSEAlgEdDSA:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			break
+		}
+		valid = ed25519.Verify(key, data, signature)
+	}
+
+	if !valid {
+		return webAuthnError("invalid signature")
+	}
+	return nil
+}
+
+// EncodeWebAuthnBytes encodes binary WebAuthn values as unpadded base64url, as used in the JSON API
+func EncodeWebAuthnBytes(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// DecodeWebAuthnBytes decodes base64url, with or without padding
+func DecodeWebAuthnBytes(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+func webAuthnError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrWebAuthnVerification, reason)
+}
@@ -0,0 +1,358 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const (
+	testRPID   = "haslaw.test"
+	testOrigin = "https://admin.haslaw.test"
+)
+
+var testWebAuthnConfig = WebAuthnConfig{RPID: testRPID, RPName: "HasLaw", Origins: []string{testOrigin}}
+
+// cborMap keeps the key order of an encoded map, which matters for signed bytes
+type cborMap []cborPair
+
+type cborPair struct {
+	key, value interface{}
+}
+
+// encodeCBOR is the small subset of a CBOR encoder the test authenticator needs
+func encodeCBOR(value interface{}) []byte {
+	var out []byte
+	head := func(major byte, n uint64) {
+		switch {
+		case n < 24:
+			out = append(out, major<<5|byte(n))
+		case n < 1<<8:
+			out = append(out, major<<5|24, byte(n))
+		case n < 1<<16:
+			out = append(out, major<<5|25)
+			out = binary.BigEndian.AppendUint16(out, uint16(n))
+		default:
+			out = append(out, major<<5|26)
+			out = binary.BigEndian.AppendUint32(out, uint32(n))
+		}
+	}
+
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			head(0, uint64(v))
+		} else {
+			head(1, uint64(-1-v))
+		}
+	case []byte:
+		head(2, uint64(len(v)))
+		out = append(out, v...)
+	case string:
+		head(3, uint64(len(v)))
+		out = append(out, v...)
+	case cborMap:
+		head(5, uint64(len(v)))
+		for _, pair := range v {
+			out = append(out, encodeCBOR(pair.key)...)
+			out = append(out, encodeCBOR(pair.value)...)
+		}
+	default:
+		panic("encodeCBOR: unsupported type")
+	}
+	return out
+}
+
+// testAuthenticator is a software authenticator holding one ES256 or Ed25519 credential
+type testAuthenticator struct {
+	credentialID []byte
+	ecKey        *ecdsa.PrivateKey
+	edKey        ed25519.PrivateKey
+}
+
+func newES256Authenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testAuthenticator{credentialID: randomBytes(t, 16), ecKey: key}
+}
+
+func newEd25519Authenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testAuthenticator{credentialID: randomBytes(t, 16), edKey: key}
+}
+
+func (a *testAuthenticator) algorithm() int64 {
+	if a.ecKey != nil {
+		return COSEAlgES256
+	}
+	return COSEAlgEdDSA
+}
+
+func (a *testAuthenticator) coseKey() []byte {
+	if a.ecKey != nil {
+		return encodeCBOR(cborMap{
+			{1, 2},
+			{3, int(COSEAlgES256)},
+			{-1, 1},
+			{-2, a.ecKey.X.FillBytes(make([]byte, 32))},
+			{-3, a.ecKey.Y.FillBytes(make([]byte, 32))},
+		})
+	}
+	return encodeCBOR(cborMap{
+		{1, 1},
+		{3, int(COSEAlgEdDSA)},
+		{-1, 6},
+		{-2, []byte(a.edKey.Public().(ed25519.PublicKey))},
+	})
+}
+
+func (a *testAuthenticator) sign(t *testing.T, data []byte) []byte {
+	t.Helper()
+	if a.ecKey == nil {
+		return ed25519.Sign(a.edKey, data)
+	}
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, a.ecKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signature
+}
+
+// authenticatorData builds rpIdHash | flags | signCount and, when attested, the credential data
+func (a *testAuthenticator) authenticatorData(rpID string, flags byte, signCount uint32, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	if attested {
+		flags |= authenticatorFlagAttested
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+// attestationObject wraps authData in a "none" or self-signed "packed" attestation
+func (a *testAuthenticator) attestationObject(t *testing.T, format string, authData, clientDataJSON []byte) []byte {
+	t.Helper()
+	statement := cborMap{}
+	if format == "packed" {
+		clientDataHash := sha256.Sum256(clientDataJSON)
+		signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+		statement = cborMap{{"alg", int(a.algorithm())}, {"sig", a.sign(t, signed)}}
+	}
+	return encodeCBOR(cborMap{{"fmt", format}, {"attStmt", statement}, {"authData", authData}})
+}
+
+// assert signs authData together with the client data hash, as navigator.credentials.get() does
+func (a *testAuthenticator) assert(t *testing.T, authData, clientDataJSON []byte) []byte {
+	t.Helper()
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	return a.sign(t, append(append([]byte{}, authData...), clientDataHash[:]...))
+}
+
+func testClientData(t *testing.T, clientDataType, challenge, origin string) []byte {
+	t.Helper()
+	data, err := json.Marshal(CollectedClientData{Type: clientDataType, Challenge: challenge, Origin: origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	value := make([]byte, n)
+	if _, err := rand.Read(value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	authenticators := map[string]func(*testing.T) *testAuthenticator{
+		"ES256":   newES256Authenticator,
+		"Ed25519": newEd25519Authenticator,
+	}
+
+	for name, newAuthenticator := range authenticators {
+		for _, format := range []string{"none", "packed"} {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				authenticator := newAuthenticator(t)
+				clientData := testClientData(t, WebAuthnTypeCreate, "challenge", testOrigin)
+				authData := authenticator.authenticatorData(testRPID, authenticatorFlagUserPresent|authenticatorFlagUserVerified, 0, true)
+
+				credential, err := VerifyRegistration(testWebAuthnConfig, "challenge", clientData, authenticator.attestationObject(t, format, authData, clientData), true)
+				if err != nil {
+					t.Fatalf("VerifyRegistration: %v", err)
+				}
+				if string(credential.ID) != string(authenticator.credentialID) {
+					t.Errorf("credential ID = %x, want %x", credential.ID, authenticator.credentialID)
+				}
+				if credential.Algorithm != authenticator.algorithm() {
+					t.Errorf("algorithm = %d, want %d", credential.Algorithm, authenticator.algorithm())
+				}
+				if credential.AttestationFormat != format || !credential.UserVerified {
+					t.Errorf("format = %q, user verified = %v", credential.AttestationFormat, credential.UserVerified)
+				}
+			})
+		}
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	authenticator := newES256Authenticator(t)
+	flags := byte(authenticatorFlagUserPresent | authenticatorFlagUserVerified)
+
+	tests := []struct {
+		name           string
+		clientDataType string
+		challenge      string
+		origin         string
+		rpID           string
+		flags          byte
+		tamper         bool
+	}{
+		{name: "wrong origin", origin: "https://evil.test"},
+		{name: "wrong rpIdHash", rpID: "evil.test"},
+		{name: "wrong challenge", challenge: "other"},
+		{name: "assertion client data", clientDataType: WebAuthnTypeGet},
+		{name: "missing user verification", flags: authenticatorFlagUserPresent},
+		{name: "missing user presence", flags: authenticatorFlagUserVerified},
+		{name: "tampered self attestation", tamper: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientDataType, challenge, origin, rpID, testFlags := WebAuthnTypeCreate, "challenge", testOrigin, testRPID, flags
+			if tt.clientDataType != "" {
+				clientDataType = tt.clientDataType
+			}
+			if tt.challenge != "" {
+				challenge = tt.challenge
+			}
+			if tt.origin != "" {
+				origin = tt.origin
+			}
+			if tt.rpID != "" {
+				rpID = tt.rpID
+			}
+			if tt.flags != 0 {
+				testFlags = tt.flags
+			}
+
+			clientData := testClientData(t, clientDataType, challenge, origin)
+			authData := authenticator.authenticatorData(rpID, testFlags, 0, true)
+			attestation := authenticator.attestationObject(t, "packed", authData, clientData)
+			if tt.tamper {
+				// Sama setelah di-parse, tetapi hash-nya berbeda dari yang ditandatangani
+				clientData = append(clientData, ' ')
+			}
+
+			if _, err := VerifyRegistration(testWebAuthnConfig, "challenge", clientData, attestation, true); !errors.Is(err, ErrWebAuthnVerification) {
+				t.Fatalf("err = %v, want ErrWebAuthnVerification", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	authenticators := map[string]func(*testing.T) *testAuthenticator{
+		"ES256":   newES256Authenticator,
+		"Ed25519": newEd25519Authenticator,
+	}
+
+	for name, newAuthenticator := range authenticators {
+		t.Run(name, func(t *testing.T) {
+			authenticator := newAuthenticator(t)
+			clientData := testClientData(t, WebAuthnTypeGet, "challenge", testOrigin)
+			authData := authenticator.authenticatorData(testRPID, authenticatorFlagUserPresent|authenticatorFlagUserVerified, 7, false)
+
+			signCount, err := VerifyAssertion(testWebAuthnConfig, "challenge", clientData, authData, authenticator.assert(t, authData, clientData), authenticator.coseKey(), 6, true)
+			if err != nil {
+				t.Fatalf("VerifyAssertion: %v", err)
+			}
+			if signCount != 7 {
+				t.Errorf("sign count = %d, want 7", signCount)
+			}
+		})
+	}
+
+	t.Run("synced passkey without counter", func(t *testing.T) {
+		authenticator := newES256Authenticator(t)
+		clientData := testClientData(t, WebAuthnTypeGet, "challenge", testOrigin)
+		authData := authenticator.authenticatorData(testRPID, authenticatorFlagUserPresent, 0, false)
+
+		if _, err := VerifyAssertion(testWebAuthnConfig, "challenge", clientData, authData, authenticator.assert(t, authData, clientData), authenticator.coseKey(), 0, false); err != nil {
+			t.Fatalf("VerifyAssertion: %v", err)
+		}
+	})
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	authenticator := newES256Authenticator(t)
+	flags := byte(authenticatorFlagUserPresent | authenticatorFlagUserVerified)
+
+	tests := []struct {
+		name            string
+		origin          string
+		rpID            string
+		flags           byte
+		signCount       uint32
+		storedSignCount uint32
+		requireUV       bool
+		badSignature    bool
+	}{
+		{name: "wrong origin", origin: "https://evil.test", signCount: 2, storedSignCount: 1},
+		{name: "wrong rpIdHash", rpID: "evil.test", signCount: 2, storedSignCount: 1},
+		{name: "sign counter went backwards", signCount: 3, storedSignCount: 5},
+		{name: "sign counter did not move", signCount: 5, storedSignCount: 5},
+		{name: "missing user verification", flags: authenticatorFlagUserPresent, signCount: 2, storedSignCount: 1, requireUV: true},
+		{name: "signature by another key", signCount: 2, storedSignCount: 1, badSignature: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, rpID, testFlags := testOrigin, testRPID, flags
+			if tt.origin != "" {
+				origin = tt.origin
+			}
+			if tt.rpID != "" {
+				rpID = tt.rpID
+			}
+			if tt.flags != 0 {
+				testFlags = tt.flags
+			}
+
+			clientData := testClientData(t, WebAuthnTypeGet, "challenge", origin)
+			authData := authenticator.authenticatorData(rpID, testFlags, tt.signCount, false)
+			signer := authenticator
+			if tt.badSignature {
+				signer = newES256Authenticator(t)
+			}
+
+			_, err := VerifyAssertion(testWebAuthnConfig, "challenge", clientData, authData, signer.assert(t, authData, clientData), authenticator.coseKey(), tt.storedSignCount, tt.requireUV)
+			if !errors.Is(err, ErrWebAuthnVerification) {
+				t.Fatalf("err = %v, want ErrWebAuthnVerification", err)
+			}
+		})
+	}
+}